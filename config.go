@@ -0,0 +1,192 @@
+package goconfigure
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// sources holds the values, other than flags, that options can be set from
+// when they are parsed by a parent Options type.
+type sources struct {
+	config map[string]interface{}
+	files  map[string]string
+	env    map[string]envValue
+	lookup func(name string) (string, bool)
+}
+
+// envValue is a variable loaded from a dotenv file.
+type envValue struct {
+	value string
+	file  string
+}
+
+// getenv returns the value of the environment variable, the dotenv file it was
+// loaded from if it is not set in the environment, and whether it is set. The
+// environment is read using lookup, or os.LookupEnv if lookup is nil.
+func (s *sources) getenv(name string) (string, string, bool) {
+	lookup := s.lookup
+
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	if v, ok := lookup(name); ok {
+		return v, "", true
+	}
+
+	e, ok := s.env[name]
+	return e.value, e.file, ok
+}
+
+// file returns the config file the value for the given key was loaded from, or
+// an empty string if it is not known.
+func (s *sources) file(key string) string {
+	return s.files[joinKey(splitKey(key))]
+}
+
+// record the file as the source of every value held in config.
+func (s *sources) record(file string, config map[string]interface{},
+	path []string) {
+	if s.files == nil {
+		s.files = map[string]string{}
+	}
+
+	for k, v := range config {
+		p := append(append([]string{}, path...), k)
+		s.files[joinKey(p)] = file
+
+		if m, ok := v.(map[string]interface{}); ok {
+			s.record(file, m, p)
+		}
+	}
+}
+
+// configFiles returns the paths held by the given options, which must be
+// either string or []string options. Empty paths are ignored.
+func configFiles(options []Option) ([]string, error) {
+	var files []string
+
+	for _, option := range options {
+		var paths []string
+
+		if option == nil {
+			continue
+		}
+
+		v := option.Value()
+		p := reflect.ValueOf(v.Pointer())
+
+		if p.Kind() == reflect.Ptr {
+			p = p.Elem()
+		}
+
+		if p.Kind() == reflect.Slice {
+			if err := v.AssignTo(&paths); err != nil {
+				return nil, fmt.Errorf("failed to read paths for config "+
+					"files: %s", err)
+			}
+		} else {
+			var path string
+
+			if err := v.AssignTo(&path); err != nil {
+				return nil, fmt.Errorf("failed to read path for config "+
+					"file: %s", err)
+			}
+
+			paths = []string{path}
+		}
+
+		for _, path := range paths {
+			if path != "" {
+				files = append(files, path)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// merge src into dst. Nested objects are merged recursively while all other
+// values in src replace those in dst.
+func merge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		d, dok := dst[k].(map[string]interface{})
+		s, sok := v.(map[string]interface{})
+
+		if dok && sok {
+			merge(d, s)
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
+// splitKey splits a dotted configuration key into its path elements. A dot can
+// be included in an element by escaping it with a backslash, as can a
+// backslash itself.
+func splitKey(key string) []string {
+	var path []string
+	b := strings.Builder{}
+	escaped := false
+
+	for _, r := range key {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			path = append(path, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return append(path, b.String())
+}
+
+// joinKey joins the path elements into a dotted configuration key, escaping
+// any dots or backslashes in the elements.
+func joinKey(path []string) string {
+	r := strings.NewReplacer(`\`, `\\`, ".", `\.`)
+	escaped := make([]string, len(path))
+
+	for i, element := range path {
+		escaped[i] = r.Replace(element)
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// lookup walks the config using the dotted key, returning the value found and
+// whether it existed. An error is returned if any element of the path other
+// than the last is not a JSON object.
+func lookup(config map[string]interface{}, key string) (interface{}, bool,
+	error) {
+	if key == "" {
+		return nil, false, nil
+	}
+
+	path := splitKey(key)
+	node := config
+
+	for i, element := range path[:len(path)-1] {
+		v, ok := node[element]
+
+		if !ok {
+			return nil, false, nil
+		}
+
+		if node, ok = v.(map[string]interface{}); !ok {
+			return nil, false, fmt.Errorf("cannot find '%s': '%s' is %T, "+
+				"not an object", key, joinKey(path[:i+1]), v)
+		}
+	}
+
+	v, ok := node[path[len(path)-1]]
+	return v, ok, nil
+}
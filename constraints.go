@@ -0,0 +1,111 @@
+package goconfigure
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+)
+
+// constraint is a rule that applies across a number of options.
+type constraint struct {
+	usage func() string
+	check func() error
+}
+
+// namer is implemented by options that can describe how they are referred to.
+type namer interface {
+	name() string
+}
+
+// nameOf returns the name of the option to use in constraint usage and errors.
+func nameOf(opt Option) string {
+	if n, ok := opt.(namer); ok {
+		return n.name()
+	}
+
+	return "option"
+}
+
+// isSet reports whether the option has been set by a flag, environment
+// variable, or config file.
+func isSet(opt Option) bool {
+	o := opt.Value().Source.Origin
+	return o != value.None && o != value.Default
+}
+
+func names(options []Option) []string {
+	n := make([]string, len(options))
+
+	for i, opt := range options {
+		n[i] = nameOf(opt)
+	}
+
+	return n
+}
+
+func mutuallyExclusive(options []Option) constraint {
+	return constraint{
+		usage: func() string {
+			return fmt.Sprintf("Only one of %s can be used.",
+				join(names(options), "or"))
+		},
+		check: func() error {
+			var set []Option
+
+			for _, opt := range options {
+				if isSet(opt) {
+					set = append(set, opt)
+				}
+			}
+
+			if len(set) > 1 {
+				return fmt.Errorf("%s cannot be used together",
+					join(names(set), "and"))
+			}
+
+			return nil
+		},
+	}
+}
+
+func requiredTogether(options []Option) constraint {
+	return constraint{
+		usage: func() string {
+			return fmt.Sprintf("%s must be used together.",
+				join(names(options), "and"))
+		},
+		check: func() error {
+			var unset []Option
+
+			for _, opt := range options {
+				if !isSet(opt) {
+					unset = append(unset, opt)
+				}
+			}
+
+			if len(unset) > 0 && len(unset) < len(options) {
+				return fmt.Errorf("%s must be used together: %s not set",
+					join(names(options), "and"), join(names(unset), "and"))
+			}
+
+			return nil
+		},
+	}
+}
+
+func requiredIf(option Option, condition string,
+	predicate func() bool) constraint {
+	return constraint{
+		usage: func() string {
+			return fmt.Sprintf("%s is required when %s.", nameOf(option),
+				condition)
+		},
+		check: func() error {
+			if predicate() && !isSet(option) {
+				return fmt.Errorf("%s is required when %s", nameOf(option),
+					condition)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"strings"
+	"testing"
+)
+
+func ExampleOptions_MutuallyExclusive() {
+	var cert string
+	var insecure bool
+
+	opts := goconfigure.NewOptionsWithArgs([]string{
+		"--tls-cert", "cert.pem", "--insecure"})
+
+	c := goconfigure.NewOption(&cert, "TLS certificate")
+	c.LongFlag("tls-cert")
+	opts.Add(c)
+
+	i := goconfigure.NewOption(&insecure, "Disable TLS")
+	i.LongFlag("insecure")
+	opts.Add(i)
+
+	opts.MutuallyExclusive(c, i)
+
+	fmt.Println(opts.Parse(nil))
+
+	// Output:
+	// config error: error parsing options: --tls-cert and --insecure cannot be used together
+}
+
+func TestOptions_Constraints(t *testing.T) {
+	type credentials struct {
+		user, password string
+		tls            bool
+		cert           string
+	}
+
+	setup := func(args []string, c *credentials) goconfigure.Options {
+		opts := goconfigure.NewOptionsWithArgs(args)
+
+		u := goconfigure.NewOption(&c.user, "user")
+		u.Flags('u', "user")
+		opts.Add(u)
+
+		p := goconfigure.NewOption(&c.password, "password")
+		p.EnvVar("UNSET_ENV_VAR")
+		opts.Add(p)
+
+		t := goconfigure.NewOption(&c.tls, "enable TLS")
+		t.LongFlag("tls")
+		opts.Add(t)
+
+		cert := goconfigure.NewOption(&c.cert, "certificate")
+		cert.ConfigKey("cert")
+		cert.Default("default.pem")
+		opts.Add(cert)
+
+		opts.RequiredTogether(u, p)
+		opts.RequiredIf(cert, "TLS is enabled", func() bool { return c.tls })
+		opts.MutuallyExclusive(u, t)
+
+		return opts
+	}
+
+	t.Run("Satisfied constraints will not error", func(t *testing.T) {
+		var c credentials
+		opts := setup([]string{"--tls"}, &c)
+		err := opts.Parse(map[string]interface{}{"cert": "cert.pem"})
+
+		if err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+	})
+
+	t.Run("All violations are reported", func(t *testing.T) {
+		var c credentials
+		opts := setup([]string{"-u", "user", "--tls"}, &c)
+		err := opts.Parse(nil)
+
+		expected := "config error: error parsing options: --user and " +
+			"$UNSET_ENV_VAR must be used together: $UNSET_ENV_VAR not set; " +
+			"config key 'cert' is required when TLS is enabled; --user and " +
+			"--tls cannot be used together"
+
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+
+	t.Run("Constraints are shown in usage", func(t *testing.T) {
+		var c credentials
+		s := setup(nil, &c).UsageString()
+
+		for _, expected := range []string{
+			"--user and $UNSET_ENV_VAR must be used together.",
+			"config key 'cert' is required when TLS is enabled.",
+			"Only one of --user or --tls can be used.",
+		} {
+			if !strings.Contains(s, expected) {
+				t.Errorf("unexpected usage string:\n%s", s)
+			}
+		}
+	})
+
+	t.Run("Options without flags are named", func(t *testing.T) {
+		var a, b string
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		x := goconfigure.NewOption(&a, "first")
+		opts.Add(x)
+		y := goconfigure.NewOption(&b, "second")
+		opts.Add(y)
+		opts.MutuallyExclusive(x, y, x)
+		s := opts.UsageString()
+
+		if !strings.Contains(s, "Only one of 'first', 'second', or 'first'") {
+			t.Errorf("unexpected usage string:\n%s", s)
+		}
+	})
+}
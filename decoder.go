@@ -0,0 +1,211 @@
+package goconfigure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Decoder decodes the contents of a configuration file into a map of config
+// keys to values. Nested objects should be decoded as map[string]interface{}
+// and arrays as []interface{}.
+type Decoder interface {
+	Decode(b []byte) (map[string]interface{}, error)
+}
+
+// DecoderFunc allows an ordinary function to be used as a Decoder.
+type DecoderFunc func(b []byte) (map[string]interface{}, error)
+
+// Decode calls f(b).
+func (f DecoderFunc) Decode(b []byte) (map[string]interface{}, error) {
+	return f(b)
+}
+
+var decoders = struct {
+	sync.RWMutex
+	formats []string
+	byName  map[string]Decoder
+}{byName: map[string]Decoder{}}
+
+func init() {
+	RegisterDecoder("json", DecoderFunc(decodeJSON))
+	RegisterDecoder("toml", DecoderFunc(decodeTOML))
+	RegisterDecoder("yaml", DecoderFunc(decodeYAML))
+	RegisterDecoder("yml", DecoderFunc(decodeYAML))
+}
+
+// RegisterDecoder registers the Decoder to use for config files with the given
+// extension, for example:
+//
+//     goconfigure.RegisterDecoder(".ini", myIniDecoder)
+//
+// The leading dot is optional and extensions are not case sensitive.
+// Registering a Decoder for an extension that already has one, including the
+// built in json, toml, yaml, and yml decoders, replaces it.
+func RegisterDecoder(ext string, decoder Decoder) {
+	ext = format(ext)
+
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	if _, ok := decoders.byName[ext]; !ok {
+		decoders.formats = append(decoders.formats, ext)
+	}
+
+	decoders.byName[ext] = decoder
+}
+
+// decode the contents of the given config file. If a format is given then the
+// Decoder registered for it is used, otherwise the file extension determines
+// the Decoder. If the file has no extension, or an unregistered one, then each
+// Decoder is tried in the order it was registered and the first to succeed is
+// used.
+func decode(file, format string, b []byte) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	var err error
+
+	if format != "" {
+		d, ok := decoder(format)
+
+		if !ok {
+			return nil, fmt.Errorf("no decoder registered for format '%s'",
+				format)
+		}
+
+		config, err = d.Decode(b)
+	} else if d, ok := decoder(filepath.Ext(file)); ok {
+		config, err = d.Decode(b)
+	} else {
+		config, err = sniff(b)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return normalise(config).(map[string]interface{}), nil
+}
+
+func decoder(ext string) (Decoder, bool) {
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	d, ok := decoders.byName[format(ext)]
+	return d, ok
+}
+
+func sniff(b []byte) (map[string]interface{}, error) {
+	decoders.RLock()
+	formats := append([]string{}, decoders.formats...)
+	decoders.RUnlock()
+
+	for _, f := range formats {
+		d, _ := decoder(f)
+
+		if config, err := d.Decode(b); err == nil {
+			return config, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to determine the config format")
+}
+
+func format(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
+
+// decodeJSON decodes numbers as json.Number so normalise can hold integers
+// without losing precision.
+func decodeJSON(b []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	if err := d.Decode(&config); err != nil {
+		return config, err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return config, errors.New("invalid data after top-level value")
+	}
+
+	return config, nil
+}
+
+func decodeTOML(b []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	err := toml.Unmarshal(b, &config)
+	return config, err
+}
+
+func decodeYAML(b []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	err := yaml.Unmarshal(b, &config)
+	return config, err
+}
+
+// normalise converts decoded values into the same shapes produced by decoding
+// JSON, with integers held as int64 (or uint64 if they are too large for an
+// int64) to avoid losing precision, and other numbers held as float64.
+// Timestamps are held as RFC 3339 strings so they can be assigned to string
+// options, as they would be if given in JSON.
+func normalise(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalise(e)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+
+		for k, e := range t {
+			m[fmt.Sprint(k)] = normalise(e)
+		}
+
+		return m
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalise(e)
+		}
+	case []map[string]interface{}:
+		s := make([]interface{}, len(t))
+
+		for i, e := range t {
+			s[i] = normalise(e)
+		}
+
+		return s
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+
+		if u, err := strconv.ParseUint(t.String(), 10, 64); err == nil {
+			return u
+		}
+
+		f, _ := t.Float64()
+		return f
+	case int:
+		return int64(t)
+	case uint:
+		return uint64(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return v
+}
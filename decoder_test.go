@@ -0,0 +1,109 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func ExampleRegisterDecoder() {
+	var options struct {
+		config  string
+		numeric string
+	}
+
+	goconfigure.RegisterDecoder(".kv", goconfigure.DecoderFunc(
+		func(b []byte) (map[string]interface{}, error) {
+			config := map[string]interface{}{}
+
+			for _, line := range strings.Fields(string(b)) {
+				if kv := strings.SplitN(line, "=", 2); len(kv) == 2 {
+					config[kv[0]] = kv[1]
+				}
+			}
+
+			return config, nil
+		}))
+
+	opts := goconfigure.NewOptionsWithArgs([]string{
+		"-f", "testdata/config.kv"})
+
+	opt := goconfigure.NewOption(&options.numeric, "from config")
+	opt.ConfigKey("numeric")
+	opts.Add(opt)
+
+	opt = goconfigure.NewOption(&options.config, "config file")
+	opt.ShortFlag('f')
+	opts.Add(opt)
+
+	if err := opts.ParseUsing(opt); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(options.numeric)
+
+	// Output:
+	// 4
+}
+
+func TestOptions_ConfigFormat(t *testing.T) {
+	parse := func(path, format string) (int, error) {
+		var config struct {
+			file    string
+			numeric int
+		}
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"--config", path})
+		opts.ConfigFormat(format)
+
+		c := goconfigure.NewOption(&config.file, "config")
+		c.LongFlag("config")
+		opts.Add(c)
+
+		opt := goconfigure.NewOption(&config.numeric, "numeric")
+		opt.ConfigKey("numeric")
+		opts.Add(opt)
+
+		return config.numeric, opts.ParseUsing(c)
+	}
+
+	t.Run("The format overrides the extension", func(t *testing.T) {
+		n, err := parse(filepath.Join("testdata", "config"), ".YAML")
+
+		if err != nil || n != 4 {
+			t.Errorf("unexpected result parsing config: %d, %v", n, err)
+		}
+	})
+
+	t.Run("Files without extensions are sniffed", func(t *testing.T) {
+		n, err := parse(filepath.Join("testdata", "config"), "")
+
+		if err != nil || n != 4 {
+			t.Errorf("unexpected result parsing config: %d, %v", n, err)
+		}
+	})
+
+	t.Run("Unregistered formats will error", func(t *testing.T) {
+		path := filepath.Join("testdata", "config")
+		_, err := parse(path, "unknown")
+
+		expected := fmt.Sprintf("error parsing config %s: no decoder "+
+			"registered for format 'unknown'", path)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing config: %v", err)
+		}
+	})
+
+	t.Run("Unrecognised content will error", func(t *testing.T) {
+		path := filepath.Join("testdata", "invalid")
+		_, err := parse(path, "")
+
+		expected := fmt.Sprintf("error parsing config %s: unable to "+
+			"determine the config format", path)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing config: %v", err)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package goconfigure
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	envKey  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	envName = regexp.MustCompile(`^(\{[A-Za-z_][A-Za-z0-9_.]*\}|` +
+		`[A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+func (o *options) EnvFile(files ...string) {
+	o.envFiles = append(o.envFiles, files...)
+}
+
+func (o *options) Environment(lookup func(name string) (string, bool)) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.environ = lookup
+}
+
+// EnvMap returns a function, for use with Options.Environment, that looks up
+// environment variables in the given map.
+func EnvMap(env map[string]string) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+// lookupEnv returns the function used to look up environment variables.
+func (o *options) lookupEnv() func(name string) (string, bool) {
+	if o.environ == nil {
+		return os.LookupEnv
+	}
+
+	return o.environ
+}
+
+// loadEnv loads the variables from each dotenv file in order.
+func (o *options) loadEnv() (map[string]envValue, error) {
+	env := map[string]envValue{}
+
+	for _, file := range o.envFiles {
+		b, err := ioutil.ReadFile(file)
+
+		if err != nil && o.skipMissing && os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading env file %s: %s", file, err)
+		}
+
+		vars, err := parseEnv(string(b), o.lookupEnv(), env)
+
+		if err != nil {
+			return nil, fmt.Errorf("error parsing env file %s: %s", file, err)
+		}
+
+		for _, v := range vars {
+			env[v[0]] = envValue{value: v[1], file: file}
+		}
+	}
+
+	return env, nil
+}
+
+// parseEnv parses the contents of a dotenv file, returning the variables it
+// sets as name, value pairs in the order they appear. References to variables
+// are expanded using the environment, then the variables already set in this
+// file, then those loaded from earlier files.
+func parseEnv(s string, environ func(name string) (string, bool),
+	loaded map[string]envValue) ([][2]string, error) {
+	var vars [][2]string
+	set := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+
+	get := func(name string) string {
+		if v, ok := environ(name); ok {
+			return v
+		}
+
+		if v, ok := set[name]; ok {
+			return v
+		}
+
+		return loaded[name].value
+	}
+
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		kv := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(kv[0])
+
+		if len(kv) != 2 || !envKey.MatchString(name) {
+			return nil, fmt.Errorf("line %d: expected NAME=value", n)
+		}
+
+		v := strings.TrimLeft(kv[1], " \t")
+
+		switch {
+		case strings.HasPrefix(v, `"`), strings.HasPrefix(v, "'"):
+			quote := v[:1]
+			v = v[1:]
+
+			// Quoted values can span multiple lines.
+			for end := closing(v, quote); end < 0; end = closing(v, quote) {
+				if i++; i == len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated quoted "+
+						"value for %s", n, name)
+				}
+
+				v += "\n" + lines[i]
+			}
+
+			end := closing(v, quote)
+			rest := strings.TrimSpace(v[end+1:])
+
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, fmt.Errorf("line %d: unexpected characters "+
+					"after quoted value for %s", n, name)
+			}
+
+			v = v[:end]
+
+			if quote == `"` {
+				v = expand(v, true, get)
+			}
+		default:
+			if c := strings.Index(v, " #"); c >= 0 {
+				v = v[:c]
+			}
+
+			v = expand(strings.TrimSpace(v), false, get)
+		}
+
+		set[name] = v
+		vars = append(vars, [2]string{name, v})
+	}
+
+	return vars, nil
+}
+
+// closing returns the index of the unescaped closing quote in s, or -1 if there
+// isn't one. Single quoted values have no escapes.
+func closing(s, quote string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == `"`:
+			i++
+		case s[i:i+1] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expand references to variables in s using get. A backslash prevents a $ from
+// being expanded and, if escapes is set, also introduces the escapes \n, \t,
+// \", and \\.
+func expand(s string, escapes bool, get func(name string) string) string {
+	b := strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '$':
+			b.WriteByte('$')
+			i++
+		case s[i] == '\\' && i+1 < len(s) && escapes:
+			i++
+
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(s[i])
+			default:
+				b.WriteString(s[i-1 : i+1])
+			}
+		case s[i] == '$' && envName.MatchString(s[i+1:]):
+			ref := envName.FindString(s[i+1:])
+			b.WriteString(get(strings.Trim(ref, "{}")))
+			i += len(ref)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
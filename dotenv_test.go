@@ -0,0 +1,341 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ExampleOptions_EnvFile() {
+	var host string
+
+	file := filepath.Join(os.TempDir(), "goconfigure-example.env")
+	_ = os.WriteFile(file, []byte("export GOCONFIGURE_HOST=localhost\n"), 0600)
+	defer func() { _ = os.Remove(file) }()
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	opt := goconfigure.NewOption(&host, "host")
+	opt.EnvVar("GOCONFIGURE_HOST")
+	opts.Add(opt)
+	opts.EnvFile(file)
+
+	if err := opts.Parse(nil); err != nil {
+		fmt.Println(err)
+	}
+
+	_, set := os.LookupEnv("GOCONFIGURE_HOST")
+	fmt.Println(host, set)
+
+	// Output:
+	// localhost false
+}
+
+func TestOptions_EnvFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// env returns a set of Options with a string option for each variable,
+	// using the given dotenv content.
+	env := func(t *testing.T, content string, names ...string) (
+		goconfigure.Options, []string) {
+		t.Helper()
+
+		file := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_"))
+		write(t, file, content)
+
+		values := make([]string, len(names))
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		for i, name := range names {
+			opt := goconfigure.NewOption(&values[i], name)
+			opt.EnvVar(name)
+			opts.Add(opt)
+		}
+
+		opts.EnvFile(file)
+		return opts, values
+	}
+
+	t.Run("Values will be parsed", func(t *testing.T) {
+		_ = os.Setenv("GOCONFIGURE_TEST_REAL", "real")
+		defer func() { _ = os.Unsetenv("GOCONFIGURE_TEST_REAL") }()
+
+		opts, values := env(t, `
+# A comment
+PLAIN=plain value # trailing comment
+export EXPORTED = exported
+SINGLE='single $PLAIN \n' # comment
+DOUBLE="double\t\"$PLAIN\" \$PLAIN \\"
+MULTI="first
+second"
+LITERAL='first
+second'
+BRACES=${PLAIN}s
+REAL=$GOCONFIGURE_TEST_REAL-$UNDEFINED_GOCONFIGURE_VAR
+EMPTY=
+`, "PLAIN", "EXPORTED", "SINGLE", "DOUBLE", "MULTI", "LITERAL", "BRACES",
+			"REAL", "EMPTY")
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := []string{"plain value", "exported", `single $PLAIN \n`,
+			"double\t\"plain value\" $PLAIN \\", "first\nsecond",
+			"first\nsecond", "plain values", "real-", ""}
+
+		for i, v := range expected {
+			if values[i] != v {
+				t.Errorf("unexpected value %d: %q", i, values[i])
+			}
+		}
+
+		if _, ok := os.LookupEnv("PLAIN"); ok {
+			t.Error("process environment was modified")
+		}
+	})
+
+	t.Run("The process environment takes precedence", func(t *testing.T) {
+		_ = os.Setenv("GOCONFIGURE_TEST_HOST", "real")
+		defer func() { _ = os.Unsetenv("GOCONFIGURE_TEST_HOST") }()
+
+		opts, values := env(t, "GOCONFIGURE_TEST_HOST=dotenv\n"+
+			"URL=http://${GOCONFIGURE_TEST_HOST}\n",
+			"GOCONFIGURE_TEST_HOST", "URL")
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if values[0] != "real" || values[1] != "http://real" {
+			t.Errorf("unexpected values: %v", values)
+		}
+	})
+
+	t.Run("Later files override earlier ones", func(t *testing.T) {
+		var name, other, copied string
+
+		base := filepath.Join(dir, "base.env")
+		override := filepath.Join(dir, "override.env")
+		write(t, base, "NAME=base\nOTHER=$NAME")
+		write(t, override, "NAME=override\nCOPY=$OTHER")
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		for p, v := range map[*string]string{
+			&name: "NAME", &other: "OTHER", &copied: "COPY"} {
+			opt := goconfigure.NewOption(p, v)
+			opt.EnvVar(v)
+			opts.Add(opt)
+		}
+
+		opts.EnvFile(base, override)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if r := fmt.Sprintln(name, other, copied); r != "override base base\n" {
+			t.Errorf("unexpected values: %s", r)
+		}
+	})
+
+	t.Run("Values record the file they came from", func(t *testing.T) {
+		opts, _ := env(t, "NAME=value", "NAME")
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		file := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_"))
+		expected := fmt.Sprintf("environment variable $NAME (%s)", file)
+
+		if s := opts.Explain(); !strings.Contains(s, expected) {
+			t.Errorf("unexpected explanation: %s", s)
+		}
+	})
+
+	for content, expected := range map[string]string{
+		"INVALID":       "line 1: expected NAME=value",
+		"\n1NAME=value": "line 2: expected NAME=value",
+		"NAME=\"unterminated\n": "line 1: unterminated quoted value " +
+			"for NAME",
+		"NAME='value' trailing": "line 1: unexpected characters after " +
+			"quoted value for NAME",
+		"NAME=\"escaped \\\" quote'": "line 1: unterminated quoted " +
+			"value for NAME",
+	} {
+		t.Run("Invalid files will error: "+expected, func(t *testing.T) {
+			opts, _ := env(t, content, "NAME")
+			err := opts.Parse(nil)
+
+			if err == nil || !strings.HasSuffix(err.Error(), expected) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("Missing files will error", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.EnvFile(filepath.Join(dir, "missing.env"))
+
+		if err := opts.Parse(nil); err == nil {
+			t.Error("expected error for missing file")
+		}
+
+		opts.SkipMissingConfig()
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+}
+
+func ExampleEnvMap() {
+	var port int
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	opts.Environment(goconfigure.EnvMap(map[string]string{"PORT": "8080"}))
+	opt := goconfigure.NewOption(&port, "port")
+	opt.EnvVar("PORT")
+	opts.Add(opt)
+
+	if err := opts.Parse(nil); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(port)
+
+	// Output:
+	// 8080
+}
+
+func TestOptions_Environment(t *testing.T) {
+	t.Run("Empty variables are set", func(t *testing.T) {
+		t.Parallel()
+
+		var message string
+		var list []string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(map[string]string{
+			"MESSAGE": "", "LIST": ""}))
+		opt := goconfigure.NewOption(&message, "message")
+		opt.EnvVar("MESSAGE")
+		opt.ConfigKey("message")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&list, "list")
+		opt.EnvVar("LIST")
+		opt.Default([]string{"default"})
+		opts.Add(opt)
+
+		err := opts.Parse(map[string]interface{}{"message": "config"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if message != "" || list == nil || len(list) != 0 {
+			t.Errorf("unexpected values: %q %#v", message, list)
+		}
+
+		if s := opts.Explain(); strings.Count(s, "environment variable") != 2 {
+			t.Errorf("unexpected explanation: %s", s)
+		}
+	})
+
+	t.Run("Empty variables are ignored by other types", func(t *testing.T) {
+		t.Parallel()
+
+		var count int
+		var timeout time.Duration
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(map[string]string{
+			"COUNT": "", "TIMEOUT": ""}))
+		opt := goconfigure.NewOption(&count, "count")
+		opt.EnvVar("COUNT")
+		opt.ConfigKey("count")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&timeout, "timeout")
+		opt.EnvVar("TIMEOUT")
+		opt.Default(time.Second)
+		opts.Add(opt)
+
+		err := opts.Parse(map[string]interface{}{"count": float64(3)})
+
+		if err != nil || count != 3 || timeout != time.Second {
+			t.Errorf("unexpected values: %d %s, %v", count, timeout, err)
+		}
+	})
+
+	t.Run("Unset variables are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		var message string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(nil))
+		opt := goconfigure.NewOption(&message, "message")
+		opt.EnvVar("MESSAGE")
+		opt.Default("default")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil || message != "default" {
+			t.Errorf("unexpected value: %q, %v", message, err)
+		}
+	})
+
+	t.Run("The environment is used for _FILE variables", func(t *testing.T) {
+		t.Parallel()
+
+		var password string
+
+		file := filepath.Join(t.TempDir(), "secret")
+		write(t, file, "hunter2")
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(map[string]string{
+			"PASSWORD_FILE": file}))
+		opt := goconfigure.NewOption(&password, "password")
+		opt.EnvVar("PASSWORD")
+		opt.EnvVarFile()
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil || password != "hunter2" {
+			t.Errorf("unexpected value: %q, %v", password, err)
+		}
+	})
+
+	t.Run("The environment is used by dotenv files", func(t *testing.T) {
+		t.Parallel()
+
+		var host, url string
+
+		file := filepath.Join(t.TempDir(), ".env")
+		write(t, file, "HOST=dotenv\nURL=http://${HOST}/$PATH")
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(map[string]string{
+			"HOST": "", "PATH": "path"}))
+		opts.EnvFile(file)
+		opt := goconfigure.NewOption(&host, "host")
+		opt.EnvVar("HOST")
+		opt.Default("default")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&url, "url")
+		opt.EnvVar("URL")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if host != "" || url != "http:///path" {
+			t.Errorf("unexpected values: %q %q", host, url)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package goconfigure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Encoder encodes a map of config keys to values as the contents of a
+// configuration file. Nested objects are given as map[string]interface{}.
+type Encoder interface {
+	Encode(config map[string]interface{}) ([]byte, error)
+}
+
+// EncoderFunc allows an ordinary function to be used as an Encoder.
+type EncoderFunc func(config map[string]interface{}) ([]byte, error)
+
+// Encode calls f(config).
+func (f EncoderFunc) Encode(config map[string]interface{}) ([]byte, error) {
+	return f(config)
+}
+
+var encoders = struct {
+	sync.RWMutex
+	byName map[string]Encoder
+}{byName: map[string]Encoder{}}
+
+func init() {
+	RegisterEncoder("json", EncoderFunc(encodeJSON))
+	RegisterEncoder("toml", EncoderFunc(encodeTOML))
+	RegisterEncoder("yaml", EncoderFunc(encodeYAML))
+	RegisterEncoder("yml", EncoderFunc(encodeYAML))
+}
+
+// RegisterEncoder registers the Encoder to use when writing config files with
+// the given extension. As with RegisterDecoder the leading dot is optional,
+// extensions are not case sensitive, and registering an Encoder for an
+// extension that already has one replaces it.
+func RegisterEncoder(ext string, encoder Encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	encoders.byName[format(ext)] = encoder
+}
+
+func encoder(ext string) (Encoder, bool) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	e, ok := encoders.byName[format(ext)]
+	return e, ok
+}
+
+func encodeJSON(config map[string]interface{}) ([]byte, error) {
+	b, err := json.MarshalIndent(config, "", "  ")
+	return append(b, '\n'), err
+}
+
+func encodeTOML(config map[string]interface{}) ([]byte, error) {
+	if err := checkTOML(nil, config); err != nil {
+		return nil, err
+	}
+
+	b := bytes.Buffer{}
+	err := toml.NewEncoder(&b).Encode(config)
+	return b.Bytes(), err
+}
+
+// checkTOML returns an error if v holds an unsigned integer, either directly or
+// in a slice or map, that is too large to be written to a TOML file, which only
+// supports 64 bit signed integers. The path is the config key holding v.
+func checkTOML(path []string, v interface{}) error {
+	r := reflect.ValueOf(v)
+
+	switch r.Kind() {
+	case reflect.Map:
+		for i := r.MapRange(); i.Next(); {
+			p := append(append([]string{}, path...), fmt.Sprint(i.Key()))
+
+			if err := checkTOML(p, i.Value().Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for n := 0; n < r.Len(); n++ {
+			if err := checkTOML(path, r.Index(n).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Uint, reflect.Uint64:
+		if r.Uint() > math.MaxInt64 {
+			return fmt.Errorf("value of config key '%s' is too large for a "+
+				"TOML integer", joinKey(path))
+		}
+	}
+
+	return nil
+}
+
+func encodeYAML(config map[string]interface{}) ([]byte, error) {
+	return yaml.Marshal(config)
+}
@@ -0,0 +1,31 @@
+package goconfigure
+
+import "strings"
+
+// Errors holds every error found while parsing a set of Options, allowing all
+// problems with a configuration to be reported at once.
+type Errors []error
+
+// Error returns each of the errors separated by a semicolon.
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+
+	return strings.Join(s, "; ")
+}
+
+// err returns nil if there are no errors, a single error if there is only one,
+// or e itself.
+func (e Errors) err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+
+	return e
+}
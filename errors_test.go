@@ -0,0 +1,34 @@
+package goconfigure_test
+
+import (
+	"errors"
+	"github.com/domdavis/goconfigure"
+	"testing"
+)
+
+func TestErrors_Error(t *testing.T) {
+	t.Run("Errors are separated by semicolons", func(t *testing.T) {
+		err := goconfigure.Errors{errors.New("one"), errors.New("two")}
+
+		if err.Error() != "one; two" {
+			t.Errorf("unexpected error string: %s", err)
+		}
+	})
+
+	t.Run("Errors can be unwrapped from Parse", func(t *testing.T) {
+		var a, b string
+		var errs goconfigure.Errors
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&a, "a")
+		opt.Required()
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&b, "b")
+		opt.Required()
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); !errors.As(err, &errs) || len(errs) != 2 {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package goconfigure
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+)
+
+// layer is a config file read from a file system.
+type layer struct {
+	fsys fs.FS
+	name string
+}
+
+func (o *options) FileSystem(fsys fs.FS) {
+	o.fsys = fsys
+}
+
+func (o *options) DefaultConfig(fsys fs.FS, name string) {
+	o.defaults = append(o.defaults, layer{fsys: fsys, name: name})
+}
+
+// loadDefaults loads and merges the default config files into src.
+func (o *options) loadDefaults(src *sources) error {
+	for _, d := range o.defaults {
+		b, err := fs.ReadFile(d.fsys, d.name)
+
+		if err != nil {
+			return fmt.Errorf("error reading default config %s: %s", d.name,
+				err)
+		}
+
+		c, err := decode(d.name, "", b)
+
+		if err != nil {
+			return fmt.Errorf("error parsing default config %s: %s", d.name,
+				err)
+		}
+
+		src.record(d.name, c, nil)
+		merge(src.config, c)
+	}
+
+	return nil
+}
+
+// readConfig reads the config file from the file system set with FileSystem,
+// or the operating system's file system if none has been set.
+func (o *options) readConfig(file string) ([]byte, error) {
+	if o.fsys == nil {
+		return ioutil.ReadFile(file)
+	}
+
+	return fs.ReadFile(o.fsys, file)
+}
+
+// statConfig returns the FileInfo for the config file, using the same file
+// system as readConfig.
+func (o *options) statConfig(file string) (fs.FileInfo, error) {
+	if o.fsys == nil {
+		return os.Stat(file)
+	}
+
+	return fs.Stat(o.fsys, file)
+}
@@ -0,0 +1,167 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func ExampleOptions_DefaultConfig() {
+	var host string
+	var port int
+
+	defaults := fstest.MapFS{
+		"defaults.yaml": {Data: []byte("host: localhost\nport: 8080\n")},
+	}
+
+	files := fstest.MapFS{
+		"etc/app.json": {Data: []byte(`{"port": 9090}`)},
+	}
+
+	opts := goconfigure.NewOptionsWithArgs([]string{"-c", "etc/app.json"})
+	opt := goconfigure.NewOption(&host, "host")
+	opt.ConfigKey("host")
+	opts.Add(opt)
+	opt = goconfigure.NewOption(&port, "port")
+	opt.ConfigKey("port")
+	opts.Add(opt)
+	opt = goconfigure.NewOption(new(string), "config file")
+	opt.ShortFlag('c')
+	opts.Add(opt)
+	opts.FileSystem(files)
+	opts.DefaultConfig(defaults, "defaults.yaml")
+
+	if err := opts.ParseUsing(opt); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(host, port)
+
+	// Output:
+	// localhost 9090
+}
+
+func TestOptions_FileSystem(t *testing.T) {
+	files := fstest.MapFS{
+		"config.json": {Data: []byte(`{"host": "example.com"}`)},
+	}
+
+	// options returns a set of Options with a host option and config file
+	// option set to file.
+	options := func(file string) (goconfigure.Options, goconfigure.Option,
+		*string) {
+		var host string
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"-c", file})
+		opt := goconfigure.NewOption(&host, "host")
+		opt.ConfigKey("host")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(new(string), "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		opts.FileSystem(files)
+
+		return opts, opt, &host
+	}
+
+	t.Run("Config files will be read from the file system", func(t *testing.T) {
+		opts, config, host := options("config.json")
+
+		if err := opts.ParseUsing(config); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if *host != "example.com" {
+			t.Errorf("unexpected host: %q", *host)
+		}
+	})
+
+	t.Run("Missing files will error", func(t *testing.T) {
+		opts, config, _ := options("missing.json")
+
+		if err := opts.ParseUsing(config); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("Missing files can be skipped", func(t *testing.T) {
+		opts, config, host := options("missing.json")
+		opts.SkipMissingConfig()
+
+		if err := opts.ParseUsing(config); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if *host != "" {
+			t.Errorf("unexpected host: %q", *host)
+		}
+	})
+}
+
+func TestOptions_DefaultConfig(t *testing.T) {
+	defaults := fstest.MapFS{
+		"base.toml":    {Data: []byte("host = \"base\"\nport = 1\n")},
+		"local.json":   {Data: []byte(`{"port": 2}`)},
+		"invalid.yaml": {Data: []byte("host: [")},
+	}
+
+	// options returns a set of Options with host and port options, and no
+	// config files.
+	options := func() (goconfigure.Options, *string, *int) {
+		var host string
+		var port int
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&host, "host")
+		opt.ConfigKey("host")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&port, "port")
+		opt.ConfigKey("port")
+		opts.Add(opt)
+
+		return opts, &host, &port
+	}
+
+	t.Run("Defaults will be layered in order", func(t *testing.T) {
+		opts, host, port := options()
+		opts.DefaultConfig(defaults, "base.toml")
+		opts.DefaultConfig(defaults, "local.json")
+
+		if err := opts.ParseUsing(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if *host != "base" || *port != 2 {
+			t.Errorf("unexpected values: %q %d", *host, *port)
+		}
+
+		if s := opts.Explain(); !strings.Contains(s, "in local.json") {
+			t.Errorf("unexpected explanation: %s", s)
+		}
+	})
+
+	t.Run("Missing defaults will error", func(t *testing.T) {
+		opts, _, _ := options()
+		opts.SkipMissingConfig()
+		opts.DefaultConfig(defaults, "missing.json")
+
+		err := opts.ParseUsing()
+
+		if err == nil || !strings.Contains(err.Error(), "missing.json") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Invalid defaults will error", func(t *testing.T) {
+		opts, _, _ := options()
+		opts.DefaultConfig(defaults, "invalid.yaml")
+
+		err := opts.ParseUsing()
+
+		if err == nil || !strings.Contains(err.Error(), "invalid.yaml") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
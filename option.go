@@ -4,7 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/domdavis/goconfigure/value"
-	"os"
+	"io/ioutil"
 	"reflect"
 	"strings"
 	"time"
@@ -13,7 +13,8 @@ import (
 // Option represents a configuration option that can be set either by flag,
 // configuration file, environment variable, or a default value with the value
 // to use being chosen in that order. Options must be one of bool, int, int64,
-// uint, unit64, float64, string, or time.Duration.
+// uint, unit64, float64, string, value.Secret, or time.Duration, or a slice or
+// string keyed map of any of these types other than value.Secret.
 type Option interface {
 
 	// Flags defines both a short and long flag for setting the option from the
@@ -61,14 +62,85 @@ type Option interface {
 
 	// EnvVar defines the name of an environment variable that can be used to
 	// set this option. The string value of the environment variable must be
-	// convertible to the type of the option.
+	// convertible to the type of the option. A variable that is set to an
+	// empty string is still used by string, slice, and map options, setting
+	// them to "" or an empty value, while other options ignore it and fall
+	// back to the config file or default.
 	EnvVar(name string)
 
+	// EnvVarFile allows the option to also be set from the contents of a file
+	// named by the environment variable, with _FILE appended to its name, as
+	// used for Docker and Kubernetes secrets. For example:
+	//
+	//     option.EnvVar("DB_PASSWORD")
+	//     option.EnvVarFile()
+	//
+	// allows:
+	//
+	//     DB_PASSWORD_FILE=/run/secrets/db myApp
+	//
+	// Leading and trailing white space is removed from the contents of the
+	// file. Parse returns an error if both forms of the environment variable
+	// are set, other than when the variable itself is empty and would be
+	// ignored by the option, as described by EnvVar.
+	EnvVarFile()
+
+	// Separator defines the string used to split environment variables into
+	// elements when the option is a slice or map. For example:
+	//
+	//     option.Separator(":")
+	//
+	// allows:
+	//
+	//     MY_APP_PATH=/bin:/usr/bin myApp
+	//
+	// Each element of a map option is a key=value pair:
+	//
+	//     MY_APP_LABELS=env=prod,team=core myApp
+	//
+	// The separator defaults to "," if not set and is ignored for options that
+	// are not slices or maps.
+	Separator(sep string)
+
 	// ConfigKey defines the key this option can use to set itself from a JSON
 	// configuration file. The value stored under this key must be convertible
-	// to the Option Type.
+	// to the Option Type. Slice options are set from JSON arrays and map
+	// options from JSON objects. Keys containing dots refer to nested objects,
+	// so:
+	//
+	//     option.ConfigKey("db.host")
+	//
+	// will use the value "localhost" from:
+	//
+	//     {"db": {"host": "localhost"}}
+	//
+	// A literal dot can be used in a key by escaping it with a backslash, for
+	// example "example\\.com".
 	ConfigKey(name string)
 
+	// Required marks the option as mandatory, causing Parse to return an error
+	// if the option is not set by a flag, environment variable, or
+	// configuration file. Any default value is ignored when checking if a
+	// required option has been set.
+	Required()
+
+	// Sensitive marks the option as holding a secret, such as a password or
+	// token, so its value is replaced with value.Redacted in usage
+	// information, errors, Explain, WriteConfig, and when the value.Data
+	// holding it is formatted. Options of type value.Secret are always
+	// sensitive.
+	Sensitive()
+
+	// Validate adds validators that the value of the option must pass once it
+	// has been chosen from the flags, environment variable, config file, and
+	// default. For example:
+	//
+	//     option.Validate(goconfigure.Min(1), goconfigure.Max(65535))
+	//
+	// Validators are run in the order they were added, with Parse returning
+	// the first failure.
+	Validate(validators ...Validator)
+
 	// Default defines a value that will be used by the option if no flags,
 	// environment variables, or configuration file values are set or found. The
 	// value must be the same type as the Option.
@@ -76,7 +148,8 @@ type Option interface {
 
 	// Value returns the encapsulated value held by this Option. Value should
 	// not be called until Parse has been called. In general Value only needs to
-	// be called by a parent Options type.
+	// be called by a parent Options type. If parsing fails then Value returns
+	// the value the Option was restored to, not the one that was rejected.
 	Value() value.Data
 
 	// RegisterFlags causes the flags defined on this option to be registered
@@ -90,21 +163,44 @@ type Option interface {
 
 	// Output the option as a human readable and formatted string.
 	String() string
+
+	// Explain outputs the value of the option, and where that value came from,
+	// as a human readable and formatted string. Explain should not be called
+	// until Parse has been called.
+	Explain() string
+}
+
+// resolution holds the values an option resolved to from its sources.
+type resolution struct {
+	env    value.Data
+	config value.Data
+	value  value.Data
 }
 
 type option struct {
 	shortFlag   rune
 	longFlag    string
 	envVar      string
+	envFile     bool
+	separator   string
 	configKey   string
 	description string
+	required    bool
+	sensitive   bool
+	validators  []Validator
 
 	short  value.Data
 	long   value.Data
 	env    value.Data
 	config value.Data
+	value  value.Data
+
+	// applied is set once a resolution has been applied to the option, after
+	// which value holds the value that was assigned.
+	applied bool
 
 	flags  *flag.FlagSet
+	list   *repeatedFlag
 	typeOf reflect.Type
 
 	backstop interface{}
@@ -113,9 +209,10 @@ type option struct {
 
 // NewOption returns an option with i being a pointer to a variable of the type
 // of this option (*bool, *int, *int64, *uint, *uint64, *float64, *string,
-// *time.Duration). The description is used when producing usage information.
-// Providing an invalid type for i will not error here, but will generate an
-// error when the Option is parsed.
+// *time.Duration, or a pointer to a slice or string keyed map of one of these,
+// such as *[]string or *map[string]int). The description is used when
+// producing usage information. Providing an invalid type for i will not error
+// here, but will generate an error when the Option is parsed.
 func NewOption(i interface{}, description string) Option {
 	if i == nil {
 		return &option{description:description}
@@ -123,7 +220,8 @@ func NewOption(i interface{}, description string) Option {
 
 	typeOf := reflect.Indirect(reflect.ValueOf(i)).Type()
 	return &option{
-		description: description, typeOf: typeOf, pointer: i}
+		description: description, typeOf: typeOf, pointer: i,
+		sensitive: typeOf == reflect.TypeOf(value.Secret(""))}
 }
 
 func (o *option) Flags(short rune, longFlag string) {
@@ -143,15 +241,45 @@ func (o *option) EnvVar(name string) {
 	o.envVar = name
 }
 
+func (o *option) EnvVarFile() {
+	o.envFile = true
+}
+
+func (o *option) Separator(sep string) {
+	o.separator = sep
+}
+
 func (o *option) ConfigKey(name string) {
 	o.configKey = name
 }
 
+func (o *option) Required() {
+	o.required = true
+}
+
+func (o *option) Sensitive() {
+	o.sensitive = true
+}
+
+func (o *option) Validate(validators ...Validator) {
+	o.validators = append(o.validators, validators...)
+}
+
 func (o *option) Default(value interface{}) {
 	o.backstop = value
 }
 
 func (o *option) Value() value.Data {
+	if o.applied {
+		return o.value
+	}
+
+	return o.choose(o.env, o.config)
+}
+
+// choose the value to use for the option based on the flags that have been
+// set, then env, config, and finally the default value.
+func (o *option) choose(env, config value.Data) value.Data {
 	var v value.Data
 
 	if o.flags != nil {
@@ -166,23 +294,29 @@ func (o *option) Value() value.Data {
 		})
 	}
 
-	if !v.Set && o.env.Set {
-		v = o.env
+	if !v.Set && env.Set {
+		v = env
 	}
 
-	if !v.Set && o.config.Set {
-		v = o.config
+	if !v.Set && config.Set {
+		v = config
+	}
+
+	if !v.Set && o.backstop != nil {
+		v = value.New(o.backstop).From(value.Source{Origin: value.Default})
 	}
 
 	if !v.Set {
 		v = value.New(o.backstop)
 	}
 
+	v.Sensitive = o.sensitive
 	return v
 }
 
 func (o *option) RegisterFlags(flags *flag.FlagSet) error {
 	o.flags = flags
+	o.list = nil
 
 	if o.flags == nil {
 		return nil
@@ -195,7 +329,8 @@ func (o *option) RegisterFlags(flags *flag.FlagSet) error {
 			return fmt.Errorf("failed to set short flag: %s", err)
 		}
 
-		o.short = v
+		o.short = v.From(value.Source{
+			Origin: value.Flag, Name: "-" + string(o.shortFlag)})
 	}
 
 	if o.longFlag != "" {
@@ -205,74 +340,184 @@ func (o *option) RegisterFlags(flags *flag.FlagSet) error {
 			return fmt.Errorf("failed to set long flag: %s", err)
 		}
 
-		o.long = v
+		o.long = v.From(value.Source{
+			Origin: value.Flag, Name: "--" + o.longFlag})
 	}
 
 	return nil
 }
 
 func (o *option) Parse(config map[string]interface{}) error {
+	return o.parse(&sources{config: config})
+}
+
+func (o *option) parse(src *sources) error {
+	r, err := o.resolve(src)
+
+	if err != nil {
+		return err
+	}
+
+	return o.apply(r)
+}
+
+// resolve the value of the option using the given sources, without changing
+// the option or the variable it points to.
+func (o *option) resolve(src *sources) (resolution, error) {
+	var r resolution
 	var err error
 
 	if o.pointer == nil {
-		return fmt.Errorf("option with description '%s' "+
+		return r, fmt.Errorf("option with description '%s' "+
 			"not registered with a value", o.description)
 	}
 
 	if reflect.TypeOf(o.pointer).Kind() != reflect.Ptr {
-		return fmt.Errorf("type Option requires pointer value(*%s, not %[1]s)",
-			o.typeOf.String())
+		return r, fmt.Errorf("type Option requires pointer value"+
+			"(*%s, not %[1]s)", o.typeOf.String())
+	}
+
+	if !value.Supported(o.pointer) {
+		return r, fmt.Errorf("option with description '%s' has unsupported "+
+			"type %s", o.description, o.typeOf)
+	}
+
+	if r.config, err = o.fromConfig(src); err != nil {
+		return r, fmt.Errorf("failed to parse option config: %s", err)
 	}
 
-	if err = o.setConfig(config); err != nil {
-		return fmt.Errorf("failed to parse option config: %s", err)
+	if r.env, err = o.fromEnv(src); err != nil {
+		return r, err
 	}
 
-	if env := os.Getenv(o.envVar); env != "" {
-		if o.env, err = value.Coerce(env, o.pointer); err != nil {
-			return fmt.Errorf("failed to parse environment option '%s': %s",
-				o.envVar, err)
+	v := o.choose(r.env, r.config)
+
+	if o.required && (v.Source.Origin == value.None ||
+		v.Source.Origin == value.Default) {
+		return r, fmt.Errorf("required option '%s' is not set: %s",
+			o.description, o.hint())
+	}
+
+	if v.Pointer() == nil && o.value.Source.Origin == value.None {
+		r.value = v
+		return r, nil
+	}
+
+	if v.Pointer() == nil {
+		// The option was set, but whatever set it has since been removed, so
+		// it is reset to the zero value of its type.
+		r.value = value.New(reflect.Zero(o.typeOf).Interface())
+		r.value.Sensitive = o.sensitive
+		return r, nil
+	}
+
+	t := reflect.New(o.typeOf)
+
+	if err = v.AssignTo(t.Interface()); err != nil {
+		return r, fmt.Errorf("failed to set option: %s", err)
+	}
+
+	r.value = value.New(t.Elem().Interface()).From(v.Source)
+	r.value.Sensitive = o.sensitive
+
+	for _, validator := range o.validators {
+		if err = validator.Validate(r.value); err != nil {
+			return r, fmt.Errorf("invalid value %s for '%s' from %s: %s",
+				display(r.value), o.description, v.Source, err)
 		}
 	}
 
-	if err = o.Value().AssignTo(o.pointer); err != nil {
+	return r, nil
+}
+
+// apply a resolution to the option, setting the variable it points to.
+func (o *option) apply(r resolution) error {
+	o.env, o.config, o.value, o.applied = r.env, r.config, r.value, true
+
+	if err := r.value.AssignTo(o.pointer); err != nil {
 		return fmt.Errorf("failed to set option: %s", err)
 	}
 
 	return nil
 }
 
-func (o *option) String() string {
-	b := strings.Builder{}
-	b.WriteString("\n  ")
-
-	switch {
-	case o.shortFlag == 0 && o.longFlag == "":
-		b.WriteString("No CLI option")
-	case o.shortFlag != 0 && o.longFlag != "":
-		b.WriteString(fmt.Sprintf("-%c, --%s", o.shortFlag, o.longFlag))
-	case o.shortFlag != 0:
-		b.WriteString(fmt.Sprintf("-%c", o.shortFlag))
-	case o.longFlag != "":
-		b.WriteString(fmt.Sprintf("--%s", o.longFlag))
+// current returns a resolution holding the current state of the option, and
+// the value of the variable it points to, so it can be restored using apply.
+// Until a resolution has been applied the value has no source, as it has not
+// been set by the option.
+func (o *option) current() resolution {
+	r := resolution{env: o.env, config: o.config}
+	p := reflect.ValueOf(o.pointer)
+
+	if p.Kind() == reflect.Ptr && !p.IsNil() {
+		r.value = value.New(p.Elem().Interface()).From(o.value.Source)
+		r.value.Sensitive = o.sensitive
 	}
 
-	b.WriteString("\n    \t")
-	b.WriteString(strings.Replace(o.description, "\n", "\n    \t", -1))
+	return r
+}
+
+func (o *option) String() string {
+	b := strings.Builder{}
+	o.header(&b)
 
 	isString := o.typeOf != nil && o.typeOf.Kind() == reflect.String
 
 	switch {
+	case o.backstop != nil && o.sensitive:
+		b.WriteString(fmt.Sprintf(" (default %s)", value.Redacted))
 	case o.backstop != nil && isString:
 		b.WriteString(fmt.Sprintf(" (default %q)", o.backstop))
 	case o.backstop != nil:
 		b.WriteString(fmt.Sprintf(" (default %v)", o.backstop))
 	}
 
+	if o.required {
+		b.WriteString(" (required)")
+	}
+
+	var rules []string
+
+	for _, validator := range o.validators {
+		if s, ok := validator.(fmt.Stringer); ok {
+			rules = append(rules, s.String())
+		}
+	}
+
+	if len(rules) > 0 {
+		b.WriteString("\n    \tValues must be ")
+		b.WriteString(strings.Join(rules, " and "))
+		b.WriteString(".")
+	}
+
+	if o.isMap() {
+		b.WriteString("\n    \tValues are given as key=value pairs.")
+	}
+
+	if (o.isMap() || o.isSlice()) && (o.shortFlag != 0 || o.longFlag != "") {
+		b.WriteString("\n    \tFlags can be repeated to give multiple values.")
+	}
+
 	if o.envVar != "" {
 		b.WriteString("\n    \tUse $")
 		b.WriteString(o.envVar)
-		b.WriteString(" to set this using environment variables.")
+	}
+
+	if o.envVar != "" && o.envFile {
+		b.WriteString(fmt.Sprintf(", or $%s_FILE to read it from a file,",
+			o.envVar))
+	}
+
+	if o.envVar != "" {
+		b.WriteString(" to set this using environment variables")
+	}
+
+	if o.envVar != "" && (o.isMap() || o.isSlice()) {
+		b.WriteString(fmt.Sprintf(", separating values with %q", o.sep()))
+	}
+
+	if o.envVar != "" {
+		b.WriteString(".")
 	}
 
 	if o.configKey != "" {
@@ -284,6 +529,134 @@ func (o *option) String() string {
 	return b.String()
 }
 
+func (o *option) Explain() string {
+	b := strings.Builder{}
+	o.header(&b)
+
+	v := o.Value()
+	b.WriteString("\n    \t")
+
+	if v.Source.Origin == value.None {
+		b.WriteString("Not set")
+	} else {
+		b.WriteString(fmt.Sprintf("Set to %s by %s", display(v), v.Source))
+	}
+
+	return b.String()
+}
+
+// hint describes the ways in which the option can be set.
+func (o *option) hint() string {
+	var ways []string
+
+	if o.shortFlag != 0 {
+		ways = append(ways, fmt.Sprintf("-%c", o.shortFlag))
+	}
+
+	if o.longFlag != "" {
+		ways = append(ways, "--"+o.longFlag)
+	}
+
+	if o.envVar != "" {
+		ways = append(ways, "$"+o.envVar)
+	}
+
+	if o.envVar != "" && o.envFile {
+		ways = append(ways, "$"+o.envVar+"_FILE")
+	}
+
+	if o.configKey != "" {
+		ways = append(ways, fmt.Sprintf("config key '%s'", o.configKey))
+	}
+
+	if len(ways) == 0 {
+		return "no flag, environment variable, or config key is defined"
+	}
+
+	return "use " + join(ways, "or")
+}
+
+// name returns the most recognisable way of referring to the option, preferring
+// flags, then the environment variable, config key, and finally description.
+func (o *option) name() string {
+	switch {
+	case o.longFlag != "":
+		return "--" + o.longFlag
+	case o.shortFlag != 0:
+		return fmt.Sprintf("-%c", o.shortFlag)
+	case o.envVar != "":
+		return "$" + o.envVar
+	case o.configKey != "":
+		return fmt.Sprintf("config key '%s'", o.configKey)
+	}
+
+	return fmt.Sprintf("'%s'", o.description)
+}
+
+// spec returns the definition of the option.
+func (o *option) spec() spec {
+	return spec{
+		key:         o.configKey,
+		description: o.description,
+		typeOf:      o.typeOf,
+		backstop:    o.backstop,
+		required:    o.required,
+		sensitive:   o.sensitive,
+		validators:  o.validators,
+	}
+}
+
+// identifiers returns the names the option can be looked up by in a Snapshot.
+func (o *option) identifiers() []string {
+	var ids []string
+
+	for _, id := range []string{o.configKey, o.longFlag, o.envVar} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	if o.shortFlag != 0 {
+		ids = append(ids, string(o.shortFlag))
+	}
+
+	return ids
+}
+
+// join the strings as a list using the conjunction, for example "a, b, or c".
+func join(s []string, conjunction string) string {
+	switch len(s) {
+	case 0:
+		return ""
+	case 1:
+		return s[0]
+	case 2:
+		return s[0] + " " + conjunction + " " + s[1]
+	}
+
+	return strings.Join(s[:len(s)-1], ", ") + ", " + conjunction + " " +
+		s[len(s)-1]
+}
+
+// header writes the flags and description of the option to b.
+func (o *option) header(b *strings.Builder) {
+	b.WriteString("\n  ")
+
+	switch {
+	case o.shortFlag == 0 && o.longFlag == "":
+		b.WriteString("No CLI option")
+	case o.shortFlag != 0 && o.longFlag != "":
+		b.WriteString(fmt.Sprintf("-%c, --%s", o.shortFlag, o.longFlag))
+	case o.shortFlag != 0:
+		b.WriteString(fmt.Sprintf("-%c", o.shortFlag))
+	case o.longFlag != "":
+		b.WriteString(fmt.Sprintf("--%s", o.longFlag))
+	}
+
+	b.WriteString("\n    \t")
+	b.WriteString(strings.Replace(o.description, "\n", "\n    \t", -1))
+}
+
 func (o *option) registerFlag(name string) (value.Data, error) {
 	var f func() interface{}
 	var ok bool
@@ -321,6 +694,27 @@ func (o *option) registerFlag(name string) (value.Data, error) {
 		v, success := o.backstop.(time.Duration)
 		f = func() interface{} { return o.flags.Duration(name, v, o.description) }
 		ok = success
+	case *value.Secret:
+		v, success := o.backstop.(value.Secret)
+		f = func() interface{} {
+			p := &v
+			o.flags.Var(p, name, o.description)
+			return p
+		}
+		ok = success
+	case *[]bool, *[]int, *[]int64, *[]uint, *[]uint64, *[]float64, *[]string,
+		*[]time.Duration, *map[string]bool, *map[string]int,
+		*map[string]int64, *map[string]uint, *map[string]uint64,
+		*map[string]float64, *map[string]string, *map[string]time.Duration:
+		if o.list == nil {
+			o.list = &repeatedFlag{pointer: reflect.New(o.typeOf).Interface()}
+		}
+
+		f = func() interface{} {
+			o.flags.Var(o.list, name, o.description)
+			return o.list.pointer
+		}
+		ok = reflect.TypeOf(o.backstop) == o.typeOf
 	default:
 		return value.Data{}, fmt.Errorf("invalid option type for flag %q: %T",
 			name, o.pointer)
@@ -332,19 +726,242 @@ func (o *option) registerFlag(name string) (value.Data, error) {
 			o.backstop, o.pointer, name)
 	}
 
-	return value.New(f()), nil
+	p := f()
+
+	if o.sensitive {
+		registered := o.flags.Lookup(name)
+		registered.Value = &sensitiveFlag{Value: registered.Value}
+		registered.DefValue = value.Redacted
+	}
+
+	return value.New(p), nil
+
+}
+
+// fromEnv returns the value of the environment variable, or the contents of the
+// file named by its _FILE form if EnvVarFile has been used.
+func (o *option) fromEnv(src *sources) (value.Data, error) {
+	if o.envVar == "" {
+		return value.Data{}, nil
+	}
+
+	env, dotenv, set := src.getenv(o.envVar)
+	set = set && (env != "" || o.allowsEmpty())
+	source := value.Source{Origin: value.EnvVar, Name: o.envVar, File: dotenv}
+
+	if file, _, ok := src.getenv(o.envVar + "_FILE"); o.envFile && ok {
+		if set {
+			return value.Data{}, fmt.Errorf("environment option '%s' cannot "+
+				"be set by both $%[1]s and $%[1]s_FILE", o.envVar)
+		}
+
+		b, err := ioutil.ReadFile(file)
+
+		if err != nil {
+			return value.Data{}, fmt.Errorf("failed to read environment "+
+				"option '%s_FILE': %s", o.envVar, err)
+		}
+
+		env, set = strings.TrimSpace(string(b)), true
+		source = value.Source{Origin: value.EnvVar, Name: o.envVar + "_FILE",
+			File: file}
+	}
 
+	if !set || (env == "" && !o.allowsEmpty()) {
+		return value.Data{}, nil
+	}
+
+	v, err := o.coerce(env)
+
+	switch {
+	case err != nil && o.sensitive:
+		return value.Data{}, fmt.Errorf("failed to parse environment option "+
+			"'%s': cannot coerce %s to %s", source.Name, value.Redacted,
+			o.typeOf)
+	case err != nil:
+		return value.Data{}, fmt.Errorf("failed to parse environment option "+
+			"'%s': %s", source.Name, err)
+	}
+
+	return v.From(source), nil
 }
 
-func (o *option) setConfig(config map[string]interface{}) error {
-	v, ok := config[o.configKey]
+func (o *option) fromConfig(src *sources) (value.Data, error) {
+	v, ok, err := lookup(src.config, o.configKey)
 
-	if ok && !reflect.TypeOf(v).ConvertibleTo(o.typeOf) {
-		return fmt.Errorf("cannot convert config type %T to %s for '%s'",
-			v, o.typeOf.String(), o.configKey)
-	} else if ok {
-		o.config = value.New(v)
+	switch {
+	case err != nil:
+		return value.Data{}, err
+	case ok && !convertible(v, o.typeOf):
+		return value.Data{}, fmt.Errorf("cannot convert config type %T to "+
+			"%s for '%s'", v, o.typeOf.String(), o.configKey)
+	case ok:
+		return value.New(v).From(value.Source{Origin: value.Config,
+			Name: o.configKey, File: src.file(o.configKey)}), nil
+	}
+
+	return value.Data{}, nil
+}
+
+// display returns the value held by v, quoting it if it is a string, or
+// value.Redacted if it is sensitive.
+func display(v value.Data) string {
+	if v.Sensitive {
+		return value.Redacted
+	}
+
+	p := reflect.ValueOf(v.Pointer())
+
+	if p.Kind() == reflect.Ptr {
+		p = p.Elem()
+	}
+
+	if p.Kind() == reflect.String {
+		return fmt.Sprintf("%q", p.Interface())
+	}
+
+	return fmt.Sprint(p.Interface())
+}
+
+func (o *option) coerce(data string) (value.Data, error) {
+	switch {
+	case !o.isSlice() && !o.isMap():
+		return value.Coerce(data, o.pointer)
+	case data == "":
+		return value.CoerceAll(nil, o.pointer)
+	}
+
+	return value.CoerceAll(strings.Split(data, o.sep()), o.pointer)
+}
+
+// allowsEmpty returns true if an empty string is a meaningful value for the
+// option, which is the case for strings, slices, and maps.
+func (o *option) allowsEmpty() bool {
+	return o.isSlice() || o.isMap() ||
+		(o.typeOf != nil && o.typeOf.Kind() == reflect.String)
+}
+
+func (o *option) sep() string {
+	if o.separator == "" {
+		return ","
+	}
+
+	return o.separator
+}
+
+func (o *option) isSlice() bool {
+	return o.typeOf != nil && o.typeOf.Kind() == reflect.Slice
+}
+
+func (o *option) isMap() bool {
+	return o.typeOf != nil && o.typeOf.Kind() == reflect.Map
+}
+
+// convertible reports whether v can be converted to typeOf, checking each
+// element in turn if both are slices or maps.
+func convertible(v interface{}, typeOf reflect.Type) bool {
+	from := reflect.TypeOf(v)
+
+	switch {
+	case from == nil:
+		return false
+	case from.Kind() == reflect.Slice && typeOf.Kind() == reflect.Slice:
+		s := reflect.ValueOf(v)
+
+		for i := 0; i < s.Len(); i++ {
+			if !convertible(s.Index(i).Interface(), typeOf.Elem()) {
+				return false
+			}
+		}
+
+		return true
+	case from.Kind() == reflect.Map && typeOf.Kind() == reflect.Map:
+		if from.Key().Kind() != reflect.String {
+			return false
+		}
+
+		m := reflect.ValueOf(v).MapRange()
+
+		for m.Next() {
+			if !convertible(m.Value().Interface(), typeOf.Elem()) {
+				return false
+			}
+		}
+
+		return true
+	case typeOf.Kind() == reflect.String && from.Kind() != reflect.String:
+		// Go converts integers to strings as runes, which is never wanted for
+		// a config value.
+		return false
+	}
+
+	return from.ConvertibleTo(typeOf)
+}
+
+// repeatedFlag is a flag.Value that accumulates each occurrence of a flag into
+// the slice or map it points to.
+type repeatedFlag struct {
+	pointer interface{}
+}
+
+func (r *repeatedFlag) String() string {
+	if r == nil || r.pointer == nil {
+		return ""
+	}
+
+	return fmt.Sprint(reflect.ValueOf(r.pointer).Elem().Interface())
+}
+
+func (r *repeatedFlag) Set(data string) error {
+	v, err := value.Coerce(data, r.pointer)
+
+	if err != nil {
+		return err
+	}
+
+	p := reflect.ValueOf(r.pointer).Elem()
+	d := reflect.ValueOf(v.Pointer())
+
+	if p.Kind() == reflect.Slice {
+		p.Set(reflect.AppendSlice(p, d))
+		return nil
+	}
+
+	if p.IsNil() {
+		p.Set(reflect.MakeMap(p.Type()))
+	}
+
+	for i := d.MapRange(); i.Next(); {
+		p.SetMapIndex(i.Key(), i.Value())
 	}
 
 	return nil
 }
+
+// sensitiveFlag is a flag.Value for a sensitive option that records if setting
+// it fails, allowing the error from the FlagSet, which includes the value, to
+// be replaced.
+type sensitiveFlag struct {
+	flag.Value
+	failed bool
+}
+
+func (s *sensitiveFlag) String() string {
+	if s == nil || s.Value == nil {
+		return ""
+	}
+
+	return value.Redacted
+}
+
+func (s *sensitiveFlag) Set(data string) error {
+	err := s.Value.Set(data)
+	s.failed = s.failed || err != nil
+	return err
+}
+
+// IsBoolFlag allows sensitive bool options to be set without a value.
+func (s *sensitiveFlag) IsBoolFlag() bool {
+	b, ok := s.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -4,9 +4,12 @@ import (
 	"flag"
 	"fmt"
 	"github.com/domdavis/goconfigure"
+	"github.com/domdavis/goconfigure/value"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func ExampleOption_Default() {
@@ -54,6 +57,29 @@ func ExampleOption_ConfigKey() {
 	// {config value}
 }
 
+func ExampleOption_ConfigKey_nested() {
+	var options struct {
+		host string
+	}
+
+	opts := goconfigure.NewOptionsWithArgs([]string{})
+	opt := goconfigure.NewOption(&options.host, "database host")
+	opt.ConfigKey("db.host")
+	opts.Add(opt)
+	err := opts.Parse(map[string]interface{}{
+		"db": map[string]interface{}{"host": "localhost"},
+	})
+
+	if err == nil {
+		fmt.Println(options)
+	} else {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {localhost}
+}
+
 func ExampleOption_EnvVar() {
 	const name = "GOCONFIGURE_TEST_VALUE"
 	var options struct {
@@ -119,6 +145,30 @@ func ExampleOption_Flags() {
 	// {test}
 }
 
+func ExampleOption_Separator() {
+	const name = "GOCONFIGURE_TEST_LIST"
+	var options struct {
+		test []string
+	}
+
+	_ = os.Setenv(name, "a:b:c")
+	opts := goconfigure.NewOptionsWithArgs([]string{})
+	opt := goconfigure.NewOption(&options.test, "test option")
+	opt.EnvVar(name)
+	opt.Separator(":")
+	opts.Add(opt)
+	err := opts.Parse(nil)
+
+	if err == nil {
+		fmt.Println(options)
+	} else {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {[a b c]}
+}
+
 func TestNewOption(t *testing.T) {
 	t.Run("option.New(nil, string) will not panic", func(t *testing.T) {
 		defer func() {
@@ -166,6 +216,20 @@ func TestRegisterFlags(t *testing.T) {
 		}
 	})
 
+	t.Run("An invalid slice default will error", func(t *testing.T) {
+		var value []string
+		opt := goconfigure.NewOption(&value, "")
+		opt.Default([]int{})
+		opt.ShortFlag('s')
+		err := opt.RegisterFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+
+		expected := "failed to set short flag: cannot use default option [] " +
+			"([]int) as *[]string for flag s"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error registering flags: %v", err)
+		}
+	})
+
 	t.Run("An invalid default will error", func(t *testing.T) {
 		var value string
 		opt := goconfigure.NewOption(&value, "")
@@ -240,6 +304,281 @@ func TestOption_Parse(t *testing.T) {
 	})
 }
 
+func TestOption_Slices(t *testing.T) {
+	t.Run("Repeated flags will be accumulated", func(t *testing.T) {
+		var value []int
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-n", "1", "--number", "2", "-n", "3"})
+		opt := goconfigure.NewOption(&value, "numbers")
+		opt.Flags('n', "number")
+		opt.Default([]int{4})
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "[1 2 3]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Invalid flag values will error", func(t *testing.T) {
+		var value []int
+		opts := goconfigure.NewOptionsWithArgs([]string{"-n", "one"})
+		opt := goconfigure.NewOption(&value, "numbers")
+		opt.ShortFlag('n')
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err == nil {
+			t.Errorf("expected error parsing option, got %v", value)
+		}
+	})
+
+	t.Run("Defaults will be used", func(t *testing.T) {
+		var value []time.Duration
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&value, "durations")
+		opt.ShortFlag('d')
+		opt.Default([]time.Duration{time.Second})
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if len(value) != 1 || value[0] != time.Second {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Environment variables are split on commas", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_LIST"
+		_ = os.Setenv(name, "1.5,2.5")
+		var value []float64
+		opt := goconfigure.NewOption(&value, "floats")
+		opt.EnvVar(name)
+
+		if err := opt.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "[1.5 2.5]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Config arrays will be used", func(t *testing.T) {
+		var value []uint
+		opt := goconfigure.NewOption(&value, "unsigned")
+		opt.ConfigKey("key")
+		err := opt.Parse(map[string]interface{}{
+			"key": []interface{}{float64(1), float64(2)}})
+
+		if err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "[1 2]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Incompatible config arrays will error", func(t *testing.T) {
+		var value []int
+		opt := goconfigure.NewOption(&value, "incompatible")
+		opt.ConfigKey("key")
+		err := opt.Parse(map[string]interface{}{
+			"key": []interface{}{float64(1), "text"}})
+
+		expected := "failed to parse option config: cannot convert config " +
+			"type []interface {} to []int for 'key'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+}
+
+func TestOption_Maps(t *testing.T) {
+	t.Run("Repeated flags will be merged", func(t *testing.T) {
+		var value map[string]string
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-l", "a=1", "--label", "b=2", "-l", "a=3"})
+		opt := goconfigure.NewOption(&value, "labels")
+		opt.Flags('l', "label")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "map[a:3 b:2]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Flags without a value will error", func(t *testing.T) {
+		var value map[string]string
+		opts := goconfigure.NewOptionsWithArgs([]string{"-l", "a"})
+		opt := goconfigure.NewOption(&value, "labels")
+		opt.ShortFlag('l')
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err == nil {
+			t.Errorf("expected error parsing option, got %v", value)
+		}
+	})
+
+	t.Run("Environment variables hold key=value pairs", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_MAP"
+		_ = os.Setenv(name, "a=1,b=2")
+		var value map[string]int
+		opt := goconfigure.NewOption(&value, "counts")
+		opt.EnvVar(name)
+
+		if err := opt.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "map[a:1 b:2]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Config objects will be used", func(t *testing.T) {
+		var value map[string]int
+		opt := goconfigure.NewOption(&value, "counts")
+		opt.ConfigKey("key")
+		opt.Default(map[string]int{"c": 3})
+		err := opt.Parse(map[string]interface{}{
+			"key": map[string]interface{}{"a": float64(1)}})
+
+		if err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if fmt.Sprint(value) != "map[a:1]" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Incompatible config objects will error", func(t *testing.T) {
+		var value map[string]int
+		opt := goconfigure.NewOption(&value, "incompatible")
+		opt.ConfigKey("key")
+		err := opt.Parse(map[string]interface{}{
+			"key": map[string]interface{}{"a": "text"}})
+
+		expected := "failed to parse option config: cannot convert config " +
+			"type map[string]interface {} to map[string]int for 'key'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+}
+
+func TestOption_ConfigKey(t *testing.T) {
+	t.Run("Escaped dots will be used literally", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "escaped")
+		opt.ConfigKey(`hosts.example\.com`)
+		err := opt.Parse(map[string]interface{}{
+			"hosts": map[string]interface{}{"example.com": "text"}})
+
+		if err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if value != "text" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Missing parent objects will be ignored", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "missing")
+		opt.ConfigKey("a.b.c")
+		opt.Default("default")
+		err := opt.Parse(map[string]interface{}{
+			"a": map[string]interface{}{}})
+
+		if err != nil {
+			t.Errorf("unexpected error parsing option: %s", err)
+		} else if value != "default" {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
+
+	t.Run("Non-object parents will error", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "invalid")
+		opt.ConfigKey("a.b.c")
+		err := opt.Parse(map[string]interface{}{
+			"a": map[string]interface{}{"b": "text"}})
+
+		expected := "failed to parse option config: cannot find 'a.b.c': " +
+			"'a.b' is string, not an object"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+
+	t.Run("Escaped dots are kept in errors", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "invalid")
+		opt.ConfigKey("example\\.com.port")
+		err := opt.Parse(map[string]interface{}{"example.com": "text"})
+
+		expected := "failed to parse option config: cannot find " +
+			"'example\\.com.port': 'example\\.com' is string, not an object"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+}
+
+func TestOption_Required(t *testing.T) {
+	t.Run("Unset required options will error", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "required")
+		opt.Flags('r', "required")
+		opt.EnvVar("UNSET_ENV_VAR")
+		opt.ConfigKey("required")
+		opt.Required()
+		err := opt.Parse(nil)
+
+		expected := "required option 'required' is not set: use -r, " +
+			"--required, $UNSET_ENV_VAR, or config key 'required'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+
+	t.Run("Defaults don't satisfy required options", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "required")
+		opt.ConfigKey("required")
+		opt.Default("default")
+		opt.Required()
+		err := opt.Parse(nil)
+
+		expected := "required option 'required' is not set: use config " +
+			"key 'required'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+
+	t.Run("Required options with no sources will error", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "required")
+		opt.Required()
+		err := opt.Parse(nil)
+
+		expected := "required option 'required' is not set: no flag, " +
+			"environment variable, or config key is defined"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing option: %v", err)
+		}
+	})
+
+	t.Run("Set required options will not error", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "required")
+		opt.ConfigKey("required")
+		opt.Required()
+		err := opt.Parse(map[string]interface{}{"required": "set"})
+
+		if err != nil || value != "set" {
+			t.Errorf("unexpected result parsing option: %q, %v", value, err)
+		}
+	})
+}
+
 func TestOption_String(t *testing.T) {
 	t.Run("No flags will be handled correctly", func(t *testing.T) {
 		s := goconfigure.NewOption(nil, "An Example").String()
@@ -299,6 +638,16 @@ func TestOption_String(t *testing.T) {
 		}
 	})
 
+	t.Run("Required options will be marked", func(t *testing.T) {
+		opt := goconfigure.NewOption(nil, "An Example")
+		opt.Required()
+		s := opt.String()
+
+		if !strings.Contains(s, "(required)") {
+			t.Errorf("unexpected usage string:\n%s", s)
+		}
+	})
+
 	t.Run("Environment variables will be displayed", func(t *testing.T) {
 		opt := goconfigure.NewOption(nil, "An Example")
 		opt.EnvVar("TEST_ENV")
@@ -309,6 +658,24 @@ func TestOption_String(t *testing.T) {
 		}
 	})
 	
+	t.Run("Map syntax will be displayed", func(t *testing.T) {
+		var value map[string]string
+		opt := goconfigure.NewOption(&value, "An Example")
+		opt.LongFlag("label")
+		opt.EnvVar("TEST_ENV")
+		s := opt.String()
+
+		for _, expected := range []string{
+			"key=value pairs",
+			"Flags can be repeated",
+			"separating values with \",\"",
+		} {
+			if !strings.Contains(s, expected) {
+				t.Errorf("unexpected usage string:\n%s", s)
+			}
+		}
+	})
+
 	t.Run("Config options will be displayed", func(t *testing.T) {
 		opt := goconfigure.NewOption(nil, "An Example")
 		opt.ConfigKey("key")
@@ -319,3 +686,290 @@ func TestOption_String(t *testing.T) {
 		}
 	})
 }
+
+func TestOption_Sensitive(t *testing.T) {
+	t.Run("Defaults will be redacted", func(t *testing.T) {
+		opt := goconfigure.NewOption(new(string), "An Example")
+		opt.Default("hunter2")
+		opt.Sensitive()
+		s := opt.String()
+
+		if strings.Contains(s, "hunter2") || !strings.Contains(s,
+			"(default ********)") {
+			t.Errorf("unexpected usage string:\n%s", s)
+		}
+	})
+
+	t.Run("Flag defaults will be redacted", func(t *testing.T) {
+		opt := goconfigure.NewOption(new(string), "An Example")
+		opt.LongFlag("password")
+		opt.Default("hunter2")
+		opt.Sensitive()
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		b := &strings.Builder{}
+		flags.SetOutput(b)
+
+		if err := opt.RegisterFlags(flags); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		flags.PrintDefaults()
+		s := b.String() + flags.Lookup("password").Value.String()
+
+		if strings.Contains(s, "hunter2") || strings.Contains(s, "panic") ||
+			flags.Lookup("password").DefValue != value.Redacted {
+			t.Errorf("unexpected defaults:\n%s", s)
+		}
+	})
+
+	t.Run("Values will be redacted", func(t *testing.T) {
+		var password string
+		opts := goconfigure.NewOptionsWithArgs([]string{"-p", "hunter2"})
+		opt := goconfigure.NewOption(&password, "password")
+		opt.ShortFlag('p')
+		opt.Sensitive()
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		v, _ := opts.Snapshot().Value("p")
+		s := fmt.Sprint(opts.Explain(), opt.Value())
+		s += fmt.Sprintf("%v %#v", v, v)
+
+		if strings.Contains(s, "hunter2") || password != "hunter2" {
+			t.Errorf("unexpected output: %s", s)
+		}
+	})
+
+	t.Run("Errors will not include values", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_SENSITIVE"
+		var pin int
+
+		_ = os.Setenv(name, "hunter2")
+		defer func() { _ = os.Unsetenv(name) }()
+
+		opt := goconfigure.NewOption(&pin, "pin")
+		opt.EnvVar(name)
+		opt.Sensitive()
+
+		if err := opt.Parse(nil); err == nil ||
+			strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		opt = goconfigure.NewOption(&pin, "pin")
+		opt.ConfigKey("pin")
+		opt.Sensitive()
+		opt.Validate(goconfigure.Max(10))
+
+		if err := opt.Parse(map[string]interface{}{
+			"pin": float64(1234)}); err == nil ||
+			strings.Contains(err.Error(), "1234") {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		for _, i := range []interface{}{&pin, new(map[string]int)} {
+			opts := goconfigure.NewOptionsWithArgs([]string{"-p", "hunter2"})
+			opt = goconfigure.NewOption(i, "pin")
+			opt.ShortFlag('p')
+			opt.Sensitive()
+			opts.Add(opt)
+
+			if err := opts.Parse(nil); err == nil ||
+				strings.Contains(err.Error(), "hunter2") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+
+		var password string
+		opt = goconfigure.NewOption(&password, "password")
+		opt.ConfigKey("password")
+		opt.Sensitive()
+		opt.Validate(goconfigure.Min(3))
+
+		if err := opt.Parse(map[string]interface{}{
+			"password": "hunter2"}); err == nil ||
+			strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Secrets are always sensitive", func(t *testing.T) {
+		var token value.Secret
+		opts := goconfigure.NewOptionsWithArgs([]string{"--token", "hunter2"})
+		opt := goconfigure.NewOption(&token, "token")
+		opt.LongFlag("token")
+		opt.ConfigKey("token")
+		opt.Default(value.Secret("default"))
+		opts.Add(opt)
+		opts.SensitiveOutput(goconfigure.Reveal)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		b := strings.Builder{}
+
+		if err := opts.WriteConfig(&b, "json"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if string(token) != "hunter2" || b.String() !=
+			"{\n  \"token\": \"hunter2\"\n}\n" {
+			t.Errorf("unexpected value: %s", b.String())
+		}
+
+		if s := opt.String() + opts.Explain(); strings.Contains(s,
+			"hunter2") || strings.Contains(s, "default\"") {
+			t.Errorf("unexpected output: %s", s)
+		}
+	})
+}
+
+func TestOption_EnvVarFile(t *testing.T) {
+	const name = "GOCONFIGURE_TEST_ENV_FILE"
+
+	file := filepath.Join(t.TempDir(), "secret")
+	write(t, file, "  hunter2\n")
+
+	t.Run("Files named by _FILE variables will be read", func(t *testing.T) {
+		var password string
+
+		_ = os.Setenv(name+"_FILE", file)
+		defer func() { _ = os.Unsetenv(name + "_FILE") }()
+
+		opt := goconfigure.NewOption(&password, "password")
+		opt.EnvVar(name)
+		opt.EnvVarFile()
+
+		if err := opt.Parse(nil); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		expected := fmt.Sprintf("hunter2 (from environment variable "+
+			"$%s_FILE (%s))", name, file)
+		if password != "hunter2" || opt.Value().String() != expected {
+			t.Errorf("unexpected value: %s", opt.Value())
+		}
+	})
+
+	t.Run("_FILE variables are ignored unless enabled", func(t *testing.T) {
+		var password string
+
+		_ = os.Setenv(name+"_FILE", file)
+		defer func() { _ = os.Unsetenv(name + "_FILE") }()
+
+		opt := goconfigure.NewOption(&password, "password")
+		opt.EnvVar(name)
+
+		if err := opt.Parse(nil); err != nil || password != "" {
+			t.Errorf("unexpected value: %q, %v", password, err)
+		}
+	})
+
+	t.Run("Setting both forms will error", func(t *testing.T) {
+		var password string
+
+		_ = os.Setenv(name, "hunter2")
+		_ = os.Setenv(name+"_FILE", file)
+		defer func() {
+			_ = os.Unsetenv(name)
+			_ = os.Unsetenv(name + "_FILE")
+		}()
+
+		opt := goconfigure.NewOption(&password, "password")
+		opt.EnvVar(name)
+		opt.EnvVarFile()
+		err := opt.Parse(nil)
+
+		expected := fmt.Sprintf("environment option '%s' cannot be set by "+
+			"both $%[1]s and $%[1]s_FILE", name)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Empty variables only conflict for strings", func(t *testing.T) {
+		var count int
+		var password string
+
+		number := filepath.Join(t.TempDir(), "number")
+		write(t, number, "42\n")
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Environment(goconfigure.EnvMap(map[string]string{
+			"COUNT": "", "COUNT_FILE": number,
+			"PASSWORD": "", "PASSWORD_FILE": file}))
+		opt := goconfigure.NewOption(&count, "count")
+		opt.EnvVar("COUNT")
+		opt.EnvVarFile()
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil || count != 42 {
+			t.Errorf("unexpected value: %d, %v", count, err)
+		}
+
+		opt = goconfigure.NewOption(&password, "password")
+		opt.EnvVar("PASSWORD")
+		opt.EnvVarFile()
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err == nil {
+			t.Error("expected error for both forms of a string variable")
+		}
+	})
+
+	t.Run("Missing files will error", func(t *testing.T) {
+		var password string
+
+		_ = os.Setenv(name+"_FILE", file+".missing")
+		defer func() { _ = os.Unsetenv(name + "_FILE") }()
+
+		opt := goconfigure.NewOption(&password, "password")
+		opt.EnvVar(name)
+		opt.EnvVarFile()
+
+		if err := opt.Parse(nil); err == nil {
+			t.Error("expected error reading missing file")
+		}
+	})
+
+	t.Run("File contents will be coerced", func(t *testing.T) {
+		var count int
+
+		_ = os.Setenv(name+"_FILE", file)
+		defer func() { _ = os.Unsetenv(name + "_FILE") }()
+
+		opt := goconfigure.NewOption(&count, "count")
+		opt.EnvVar(name)
+		opt.EnvVarFile()
+		err := opt.Parse(nil)
+
+		if err == nil || !strings.Contains(err.Error(), name+"_FILE") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Usage will include the _FILE variable", func(t *testing.T) {
+		opt := goconfigure.NewOption(new(string), "An Example")
+		opt.EnvVar("TEST_ENV")
+		opt.EnvVarFile()
+		opt.Required()
+
+		if s := opt.String(); !strings.Contains(s, "Use $TEST_ENV, or "+
+			"$TEST_ENV_FILE to read it from a file, to set this using "+
+			"environment variables.") {
+			t.Errorf("unexpected usage string:\n%s", s)
+		}
+
+		err := opt.Parse(nil)
+
+		const expected = "required option 'An Example' is not set: use " +
+			"$TEST_ENV or $TEST_ENV_FILE"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -1,12 +1,17 @@
 package goconfigure
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"github.com/domdavis/goconfigure/value"
+	"io"
+	"io/fs"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Options holds a set of configuration options which can be provided by the
@@ -15,12 +20,217 @@ type Options interface {
 	// Add an option to this set of Options.
 	Add(option Option)
 
-	// Parse the Options using the provided map for configuration options.
+	// Parse the Options using the provided map for configuration options. All
+	// options are parsed, even if one of them fails, and if more than one
+	// fails the returned error wraps an Errors type holding every failure.
 	Parse(config map[string]interface{}) error
 
-	// ParseUsing uses the given Option to locate and load the configuration
-	// from a file.
-	ParseUsing(option Option) error
+	// ParseUsing uses the given Options to locate and load the configuration
+	// from one or more files. Each Option can be either a string or a []string
+	// option, allowing a repeatable flag to provide multiple files. Files are
+	// loaded in order with later files being merged over earlier ones, so
+	// given:
+	//
+	//     myApp -c base.json -c prod.json
+	//
+	// values in prod.json will override those in base.json. Nested objects are
+	// merged, while any other value, including arrays, is replaced. The Decoder
+	// used to parse the file is chosen using the format set with ConfigFormat,
+	// or the file extension if no format is set.
+	// If the file has no extension, or an extension with no registered
+	// Decoder, then each registered Decoder is tried in turn. JSON, TOML, and
+	// YAML files are supported by default and other formats can be added using
+	// RegisterDecoder.
+	ParseUsing(options ...Option) error
+
+	// Environment sets the function used to look up environment variables,
+	// which defaults to os.LookupEnv. The function returns the value of the
+	// variable and whether it is set, allowing variables that are set to an
+	// empty string to be told apart from those that are not set. This allows
+	// the environment to be provided without modifying the process
+	// environment, for example in tests:
+	//
+	//     opts.Environment(goconfigure.EnvMap(map[string]string{
+	//         "PORT": "8080",
+	//     }))
+	//
+	// The environment is also used in place of the process environment by
+	// EnvFile.
+	Environment(lookup func(name string) (string, bool))
+
+	// SkipMissingConfig causes ParseUsing to ignore configuration files, and
+	// any files given to EnvFile, that do not exist rather than returning an
+	// error.
+	SkipMissingConfig()
+
+	// EnvFile loads environment variables from the given dotenv files when
+	// the Options are parsed or reloaded. Each line of a dotenv file sets a
+	// variable:
+	//
+	//     # Comments and blank lines are ignored
+	//     export HOST=localhost
+	//     URL="https://${HOST}:8080"
+	//     GREETING='Hello,
+	//     World!'
+	//
+	// Quoted values can span multiple lines. Single quoted values are used
+	// literally, while double quoted values can use the escapes \n, \t, \",
+	// \\, and \$. Both unquoted and double quoted values expand ${VAR} and
+	// $VAR using the environment and any variables already loaded. Later
+	// files override earlier ones, but variables set in the environment, as
+	// given by Environment, always take precedence over those in a dotenv
+	// file. The process environment itself is not changed.
+	EnvFile(files ...string)
+
+	// FileSystem sets the file system that ParseUsing loads config files from,
+	// which defaults to the operating system's file system. This allows config
+	// files to be loaded from an embed.FS, or an fstest.MapFS in tests. Paths
+	// are given as described by io/fs, so are always slash separated and
+	// unrooted. Dotenv files given to EnvFile are still loaded from the
+	// operating system's file system.
+	FileSystem(fsys fs.FS)
+
+	// DefaultConfig adds a config file, read from the given file system, that
+	// ParseUsing loads before any other config files. Values in the files
+	// named by the options given to ParseUsing are merged over those in the
+	// default, allowing a default config to be embedded in the application:
+	//
+	//     //go:embed defaults.yaml
+	//     var defaults embed.FS
+	//
+	//     opts.DefaultConfig(defaults, "defaults.yaml")
+	//     err := opts.ParseUsing(config)
+	//
+	// Default config files are layered in the order they are added. The
+	// format of a default config file is always taken from its extension,
+	// and it is an error for it to be missing, even if SkipMissingConfig has
+	// been used.
+	DefaultConfig(fsys fs.FS, name string)
+
+	// ConfigFormat sets the format of the configuration file used by
+	// ParseUsing, overriding the file extension. The format is the extension
+	// the Decoder was registered with, for example "yaml".
+	ConfigFormat(format string)
+
+	// MutuallyExclusive declares that at most one of the given options can be
+	// set by a flag, environment variable, or config file.
+	MutuallyExclusive(options ...Option)
+
+	// RequiredTogether declares that if any of the given options are set by a
+	// flag, environment variable, or config file then they all must be.
+	RequiredTogether(options ...Option)
+
+	// RequiredIf declares that the option must be set by a flag, environment
+	// variable, or config file if predicate returns true. The predicate is
+	// called once all options have been parsed, so it can safely use the
+	// values of other options. The condition describes when the option is
+	// required and is used in usage information and errors, for example:
+	//
+	//     opts.RequiredIf(cert, "TLS is enabled", func() bool { return tls })
+	RequiredIf(option Option, condition string, predicate func() bool)
+
+	// OnChange registers a function to be called for each option whose value
+	// is changed when the configuration is reloaded, for example by Watch. The
+	// function is given the option along with its previous and current value,
+	// and is called once the reload is complete, so may use the Options.
+	OnChange(f func(option Option, previous, current value.Data))
+
+	// OnError registers a function to be called with any error that occurs
+	// when the configuration is reloaded in the background, for example by
+	// Watch. Errors are discarded if no function is registered.
+	OnError(f func(err error))
+
+	// WatchInterval sets how often Watch checks the config files for changes.
+	// The interval defaults to one second.
+	WatchInterval(interval time.Duration)
+
+	// Watch monitors the config files loaded by ParseUsing, and any dotenv
+	// files given to EnvFile, reloading the configuration whenever one of
+	// them changes, including changes made before Watch was called that have
+	// not yet been loaded. Environment variables are read again when reloading,
+	// while flags continue to take precedence. New values are only applied if
+	// every option is valid, otherwise the previous values are kept and the
+	// error is passed to any OnError functions. Watch blocks until the
+	// context is done, so will generally be run in its own goroutine:
+	//
+	//     go opts.Watch(ctx)
+	//
+	// Watch returns an error if there are no files to watch.
+	Watch(ctx context.Context) error
+
+	// Reload the configuration using the same config files, or config map,
+	// that were last used to parse the Options. Environment variables are read
+	// again, while flags continue to take precedence. As with Watch, the new
+	// values are only applied if every option is valid, and OnChange functions
+	// are called for each option whose value changed. An option that is no
+	// longer set by anything, and has no default, is reset to its zero value.
+	// Reload returns an error if the Options have not yet been parsed.
+	Reload() error
+
+	// ReloadOnSignal reloads the configuration each time the process receives
+	// one of the given signals, or SIGHUP if no signals are given, until the
+	// context is done. ReloadOnSignal does not block. If reloading fails then
+	// the previous values are kept and the error is passed to any OnError
+	// functions.
+	ReloadOnSignal(ctx context.Context, signals ...os.Signal)
+
+	// Snapshot returns an immutable copy of the current value of every option.
+	// The variables given to NewOption are updated in place when Options are
+	// parsed, which is a data race if the configuration is reloaded while
+	// other goroutines are reading them. Those goroutines should use a
+	// Snapshot instead, which is replaced as a whole once reloading succeeds,
+	// so a consistent configuration is always seen. The Snapshot is empty
+	// until the Options have been parsed.
+	Snapshot() *Snapshot
+
+	// WriteConfig writes the current value of every option with a ConfigKey to
+	// w as a config file in the given format, which is the extension an
+	// Encoder was registered with, for example "yaml". JSON is used if no
+	// format is given. Dotted config keys are written as nested objects, so
+	// the output can be loaded using ParseUsing. This allows a config file to
+	// be bootstrapped from flags and environment variables:
+	//
+	//     myApp --port 80 --dump-config > prod.json
+	//
+	// The values of Sensitive options are masked unless SensitiveOutput is
+	// used to omit or reveal them. TOML only supports signed 64 bit integers,
+	// so writing TOML returns an error if an unsigned option holds a value
+	// larger than math.MaxInt64. WriteConfig should not be called until Parse
+	// or ParseUsing has been called.
+	WriteConfig(w io.Writer, format string) error
+
+	// SensitiveOutput sets how WriteConfig writes the values of Sensitive
+	// options. Values are masked by default.
+	SensitiveOutput(r Redaction)
+
+	// WriteSample writes an example config file to w in the given format,
+	// listing every option with a ConfigKey along with its default value, or
+	// the zero value for its type if it has no default. YAML and TOML samples
+	// include a comment for each option giving its description, type, and
+	// default. Other formats, including JSON, do not support comments so
+	// WriteSampleDescriptions can be used to write the descriptions to a
+	// separate file. JSON is used if no format is given. The defaults of
+	// Sensitive options are not written. As with WriteConfig, TOML samples
+	// cannot hold unsigned defaults larger than math.MaxInt64.
+	WriteSample(w io.Writer, format string) error
+
+	// WriteSampleDescriptions writes a JSON object to w describing each option
+	// written by WriteSample, keyed by ConfigKey, for use alongside samples in
+	// formats that do not support comments.
+	WriteSampleDescriptions(w io.Writer) error
+
+	// Strict causes Parse and ParseUsing to return an error for every key in
+	// the configuration that is not used by any option, suggesting the closest
+	// ConfigKey in case the key was mistyped. Strict also causes JSONSchema to
+	// disallow additional properties in every object.
+	Strict()
+
+	// JSONSchema returns a JSON Schema (draft 2020-12) describing the config
+	// files accepted by the Options, built from the ConfigKey, type,
+	// description, default, and validators of each option. Options marked as
+	// Required are listed as required properties. Durations are given in
+	// nanoseconds.
+	JSONSchema() ([]byte, error)
 
 	// NArg is the number of arguments remaining after flags have been
 	// processed. Calling NArg before Parse will simply return 0.
@@ -36,12 +246,43 @@ type Options interface {
 	// UsageString building of custom usage output by providing just the usage
 	// details for the defined options.
 	UsageString() string
+
+	// Explain returns a report of the value of each option and where that
+	// value came from. Explain should not be called until Parse or ParseUsing
+	// has been called.
+	Explain() string
+}
+
+// parser is implemented by options that can be parsed using the full set of
+// sources available to a parent Options type, rather than just a config map,
+// and which can be resolved separately from having their new value applied.
+type parser interface {
+	resolve(src *sources) (resolution, error)
+	apply(r resolution) error
+	current() resolution
 }
 
 type options struct {
-	data  []Option
-	args  []string
-	flags *flag.FlagSet
+	data        []Option
+	args        []string
+	format      string
+	skipMissing bool
+	constraints []constraint
+	files       []string
+	envFiles    []string
+	fsys        fs.FS
+	defaults    []layer
+	environ     func(name string) (string, bool)
+	source      func() (*sources, error)
+	onChange    []func(option Option, previous, current value.Data)
+	onError     []func(err error)
+	interval    time.Duration
+	loaded      string
+	lock        sync.Mutex
+	snapshot    atomic.Value
+	redaction   Redaction
+	strict      bool
+	flags       *flag.FlagSet
 }
 
 // NewOptions returns a new Options type that takes its flags from the arguments
@@ -70,43 +311,86 @@ func (o *options) Parse(config map[string]interface{}) error {
 		return fmt.Errorf("config error: %s", err)
 	}
 
-	if err := o.parseConfig(config); err != nil {
-		return fmt.Errorf("config error: %s", err)
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.files = nil
+	o.source = func() (*sources, error) {
+		return &sources{config: config}, nil
+	}
+
+	src, err := o.gather()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := o.parseConfig(src); err != nil {
+		return fmt.Errorf("config error: %w", err)
 	}
 
 	return nil
 }
 
-func (o *options) ParseUsing(option Option) error {
-	var file string
-	config := map[string]interface{}{}
-
+func (o *options) ParseUsing(options ...Option) error {
 	if err := o.parseFlags(); err != nil {
 		return fmt.Errorf("config error: %s", err)
 	}
 
-	if option != nil {
-		path := option.Value()
-		if err := path.AssignTo(&file); err != nil {
-			return fmt.Errorf("failed to read path for config file: %s", err)
-		}
+	files, err := configFiles(options)
+
+	if err != nil {
+		return err
 	}
 
-	if file != "" {
-		if b, err := ioutil.ReadFile(file); err != nil {
-			return fmt.Errorf("error reading config %s: %s", file, err)
-		} else if err := json.Unmarshal(b, &config); err != nil {
-			return fmt.Errorf("error parsing config %s: %s", file, err)
-		}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.files = files
+	o.source = func() (*sources, error) { return o.load(files) }
+	src, err := o.gather()
+
+	if err != nil {
+		return err
 	}
 
-	if err := o.parseConfig(config); err != nil {
-		return fmt.Errorf("config error: %s", err)
+	if _, err := o.parseConfig(src); err != nil {
+		return fmt.Errorf("config error: %w", err)
 	}
 
 	return nil
 }
 
+func (o *options) SkipMissingConfig() {
+	o.skipMissing = true
+}
+
+func (o *options) ConfigFormat(format string) {
+	o.format = format
+}
+
+func (o *options) MutuallyExclusive(options ...Option) {
+	o.constraints = append(o.constraints, mutuallyExclusive(options))
+}
+
+func (o *options) RequiredTogether(options ...Option) {
+	o.constraints = append(o.constraints, requiredTogether(options))
+}
+
+func (o *options) RequiredIf(option Option, condition string,
+	predicate func() bool) {
+	o.constraints = append(o.constraints,
+		requiredIf(option, condition, predicate))
+}
+
+func (o *options) Snapshot() *Snapshot {
+	if s, ok := o.snapshot.Load().(*Snapshot); ok {
+		return s
+	}
+
+	return &Snapshot{}
+}
+
 func (o *options) NArg() int {
 	return o.flags.NArg()
 }
@@ -135,6 +419,78 @@ func (o *options) UsageString() string {
 		b.WriteString("    \tNo configuration options set")
 	}
 
+	if len(o.constraints) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, c := range o.constraints {
+		b.WriteString("\n  ")
+		b.WriteString(c.usage())
+	}
+
+	b.WriteString("\n")
+	return b.String()
+}
+
+// gather the config and environment values to parse the options with.
+func (o *options) gather() (*sources, error) {
+	o.loaded = o.fingerprint()
+	src, err := o.source()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if src.env, err = o.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	src.lookup = o.lookupEnv()
+	return src, nil
+}
+
+// load and merge the default config files, then the given config files, in
+// order.
+func (o *options) load(files []string) (*sources, error) {
+	src := &sources{config: map[string]interface{}{}}
+
+	if err := o.loadDefaults(src); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		b, err := o.readConfig(file)
+
+		if err != nil && o.skipMissing && os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading config %s: %s", file, err)
+		}
+
+		c, err := decode(file, o.format, b)
+
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config %s: %s", file, err)
+		}
+
+		src.record(file, c, nil)
+		merge(src.config, c)
+	}
+
+	return src, nil
+}
+
+func (o *options) Explain() string {
+	b := strings.Builder{}
+
+	for _, opt := range o.data {
+		b.WriteString(opt.Explain())
+	}
+
+	if len(o.data) == 0 {
+		b.WriteString("    \tNo configuration options set")
+	}
+
 	b.WriteString("\n")
 	return b.String()
 }
@@ -146,19 +502,87 @@ func (o *options) parseFlags() error {
 		}
 	}
 
-	if err := o.flags.Parse(o.args); err != nil {
+	// The FlagSet writes errors to its output, so they are held back until
+	// any values given to sensitive flags have been redacted.
+	out := o.flags.Output()
+	b := strings.Builder{}
+	o.flags.SetOutput(&b)
+	err := o.flags.Parse(o.args)
+	o.flags.SetOutput(out)
+
+	o.flags.VisitAll(func(f *flag.Flag) {
+		if s, ok := f.Value.(*sensitiveFlag); ok && s.failed {
+			err = fmt.Errorf("invalid value %s for flag -%s", value.Redacted,
+				f.Name)
+			b.Reset()
+			b.WriteString(err.Error() + "\n")
+		}
+	})
+
+	_, _ = io.WriteString(out, b.String())
+
+	if err != nil {
 		return fmt.Errorf("failed to parse flags: %s", err)
 	}
 
 	return nil
 }
 
-func (o *options) parseConfig(config map[string]interface{}) error {
-	for _, opt := range o.data {
-		if err := opt.Parse(config); err != nil {
-			return fmt.Errorf("error parsing options: %s", err)
+// parseConfig resolves every option using the given sources. The new values
+// are kept only if every option resolves and all of the constraints are met,
+// otherwise every option is restored to its previous value. The options whose
+// value changed are returned so they can be passed to notify.
+func (o *options) parseConfig(src *sources) ([]change, error) {
+	var errs Errors
+	previous := make([]resolution, len(o.data))
+	updated := make([]resolution, len(o.data))
+	resolved := make([]bool, len(o.data))
+
+	for i, opt := range o.data {
+		var err error
+
+		if p, ok := opt.(parser); ok {
+			previous[i] = p.current()
+			updated[i], err = p.resolve(src)
+			resolved[i] = err == nil
+		} else {
+			err = opt.Parse(src.config)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	if o.strict {
+		errs = append(errs, o.unknown(src)...)
+	}
+
+	for i, opt := range o.data {
+		if p, ok := opt.(parser); ok && resolved[i] {
+			if err := p.apply(updated[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, c := range o.constraints {
+		if err := c.check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		for i, opt := range o.data {
+			if p, ok := opt.(parser); ok {
+				_ = p.apply(previous[i])
+			}
+		}
+
+		return nil, fmt.Errorf("error parsing options: %w", err)
+	}
+
+	o.snapshot.Store(newSnapshot(o.data))
+
+	return o.changes(previous, updated), nil
 }
@@ -3,6 +3,7 @@ package goconfigure_test
 import (
 	"fmt"
 	"github.com/domdavis/goconfigure"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -152,6 +153,47 @@ func ExampleOptions_Add() {
 	// {true 1 2 3 4 5.6 words 60000000000}
 }
 
+func ExampleOptions_Explain() {
+	var options struct {
+		config  string
+		numeric int
+		text    string
+	}
+
+	opts := goconfigure.NewOptionsWithArgs([]string{
+		"-f", filepath.Join("testdata", "config.json")})
+
+	opt := goconfigure.NewOption(&options.numeric, "from config")
+	opt.ConfigKey("numeric")
+	opts.Add(opt)
+
+	opt = goconfigure.NewOption(&options.text, "from default")
+	opt.EnvVar("UNSET_ENV_VAR")
+	opt.Default("unset")
+	opts.Add(opt)
+
+	opt = goconfigure.NewOption(&options.config, "config file")
+	opt.ShortFlag('f')
+	opts.Add(opt)
+
+	if err := opts.ParseUsing(opt); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Print(opts.Explain())
+
+	// Output:
+	//   No CLI option
+	//     	from config
+	//     	Set to 4 by config key 'numeric' in testdata/config.json
+	//   No CLI option
+	//     	from default
+	//     	Set to "unset" by default
+	//   -f
+	//     	config file
+	//     	Set to "testdata/config.json" by flag -f
+}
+
 func TestOptions_Parse(t *testing.T) {
 	t.Run("Parsing with invalid flags will error", func(t *testing.T) {
 		opts := goconfigure.NewOptionsWithArgs([]string{"--undefined"})
@@ -180,6 +222,32 @@ func TestOptions_Parse(t *testing.T) {
 		}
 	})
 
+	t.Run("All missing required options are reported", func(t *testing.T) {
+		var port int
+		var host string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&port, "port")
+		opt.Flags('p', "port")
+		opt.Required()
+		opts.Add(opt)
+
+		opt = goconfigure.NewOption(&host, "host")
+		opt.EnvVar("UNSET_ENV_VAR")
+		opt.ConfigKey("host")
+		opt.Required()
+		opts.Add(opt)
+		err := opts.Parse(nil)
+
+		expected := "config error: error parsing options: required option " +
+			"'port' is not set: use -p or --port; required option 'host' " +
+			"is not set: use $UNSET_ENV_VAR or config key 'host'"
+
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+
 	t.Run("Parsing config with an invalid type will error", func(t *testing.T) {
 		opts := goconfigure.NewOptionsWithArgs(nil)
 		opt := goconfigure.NewOption([]string{}, "invalid")
@@ -274,6 +342,32 @@ func TestOptions_ParseUsing(t *testing.T) {
 		}
 	})
 
+	t.Run("Numbers cannot be used for strings", func(t *testing.T) {
+		for content, pointer := range map[string]interface{}{
+			`{"key": 5}`:      new(string),
+			`{"key": [1, 2]}`: new([]string),
+		} {
+			file := filepath.Join(t.TempDir(), "config.json")
+			write(t, file, content)
+
+			var config string
+			opts := goconfigure.NewOptionsWithArgs([]string{"-c", file})
+			c := goconfigure.NewOption(&config, "config")
+			c.ShortFlag('c')
+			opts.Add(c)
+			v := goconfigure.NewOption(pointer, "value")
+			v.ConfigKey("key")
+			opts.Add(v)
+			err := opts.ParseUsing(c)
+
+			if err == nil || !strings.Contains(err.Error(),
+				"cannot convert config type") ||
+				!strings.HasSuffix(err.Error(), "for 'key'") {
+				t.Errorf("unexpected error parsing %s: %v", content, err)
+			}
+		}
+	})
+
 	t.Run("Parsing with an invalid option will error", func(t *testing.T) {
 		var config int
 
@@ -292,6 +386,243 @@ func TestOptions_ParseUsing(t *testing.T) {
 	})
 }
 
+func TestOptions_ParseUsingLayers(t *testing.T) {
+	type layered struct {
+		files []string
+		name  string
+		host  string
+		port  int
+		tags  []string
+	}
+
+	parse := func(opts goconfigure.Options, config *layered) error {
+		c := goconfigure.NewOption(&config.files, "config")
+		c.ShortFlag('c')
+		opts.Add(c)
+
+		for key, p := range map[string]interface{}{
+			"name":    &config.name,
+			"db.host": &config.host,
+			"db.port": &config.port,
+			"tags":    &config.tags,
+		} {
+			opt := goconfigure.NewOption(p, key)
+			opt.ConfigKey(key)
+			opts.Add(opt)
+		}
+
+		return opts.ParseUsing(c)
+	}
+
+	t.Run("Later files are merged over earlier ones", func(t *testing.T) {
+		var config layered
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-c", filepath.Join("testdata", "base.json"),
+			"-c", filepath.Join("testdata", "prod.yaml"),
+		})
+
+		if err := parse(opts, &config); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+
+		s := fmt.Sprintf("%s %s %d %v", config.name, config.host,
+			config.port, config.tags)
+		if s != "base db.example.com 5432 [prod]" {
+			t.Errorf("unexpected config: %s", s)
+		}
+	})
+
+	t.Run("The file supplying each value is recorded", func(t *testing.T) {
+		var config layered
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-c", filepath.Join("testdata", "base.json"),
+			"-c", filepath.Join("testdata", "prod.yaml"),
+		})
+
+		if err := parse(opts, &config); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+
+		s := opts.Explain()
+		for _, expected := range []string{
+			"config key 'db.host' in " + filepath.Join("testdata", "prod.yaml"),
+			"config key 'db.port' in " + filepath.Join("testdata", "base.json"),
+		} {
+			if !strings.Contains(s, expected) {
+				t.Errorf("unexpected explanation: %s", s)
+			}
+		}
+	})
+
+	t.Run("Missing files will error", func(t *testing.T) {
+		var config layered
+		path := filepath.Join("testdata", "missing.json")
+		opts := goconfigure.NewOptionsWithArgs([]string{"-c", path})
+
+		prefix := fmt.Sprintf("error reading config %s:", path)
+		if err := parse(opts, &config); err == nil ||
+			!strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+
+	t.Run("Missing files can be skipped", func(t *testing.T) {
+		var config layered
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-c", filepath.Join("testdata", "base.json"),
+			"-c", filepath.Join("testdata", "missing.json"),
+		})
+		opts.SkipMissingConfig()
+
+		if err := parse(opts, &config); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		} else if config.host != "localhost" {
+			t.Errorf("unexpected config: %v", config)
+		}
+	})
+
+	t.Run("Multiple options can provide files", func(t *testing.T) {
+		var base, prod, host string
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		b := goconfigure.NewOption(&base, "base")
+		b.Default(filepath.Join("testdata", "base.json"))
+		opts.Add(b)
+
+		p := goconfigure.NewOption(&prod, "prod")
+		p.Default(filepath.Join("testdata", "prod.yaml"))
+		opts.Add(p)
+
+		opt := goconfigure.NewOption(&host, "host")
+		opt.ConfigKey("db.host")
+		opts.Add(opt)
+
+		if err := opts.ParseUsing(b, p); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		} else if host != "db.example.com" {
+			t.Errorf("unexpected host: %s", host)
+		}
+	})
+}
+
+func TestOptions_ParseUsingYAML(t *testing.T) {
+	t.Run("YAML config files will be used", func(t *testing.T) {
+		var config struct {
+			file     string
+			numeric  int
+			host     string
+			ports    []uint
+			id       int64
+			released string
+		}
+
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"--config", filepath.Join("testdata", "config.yaml")})
+		c := goconfigure.NewOption(&config.file, "config")
+		c.LongFlag("config")
+		opts.Add(c)
+
+		for key, p := range map[string]interface{}{
+			"numeric":  &config.numeric,
+			"db.host":  &config.host,
+			"db.ports": &config.ports,
+			"db.id":    &config.id,
+			"released": &config.released,
+		} {
+			opt := goconfigure.NewOption(p, key)
+			opt.ConfigKey(key)
+			opts.Add(opt)
+		}
+
+		if err := opts.ParseUsing(c); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+
+		s := fmt.Sprintf("%v %v %v %v %v", config.numeric, config.host,
+			config.ports, config.id, config.released)
+		const expected = "4 localhost [80 443] 9007199254740993 " +
+			"2024-01-02T00:00:00Z"
+		if s != expected {
+			t.Errorf("unexpected config: %s", s)
+		}
+	})
+
+	t.Run("Invalid YAML will error", func(t *testing.T) {
+		var config string
+
+		path := filepath.Join("testdata", "invalid.yaml")
+		opts := goconfigure.NewOptionsWithArgs([]string{"--config", path})
+		opt := goconfigure.NewOption(&config, "config")
+		opt.LongFlag("config")
+		opts.Add(opt)
+		err := opts.ParseUsing(opt)
+
+		prefix := fmt.Sprintf("error parsing config %s: yaml:", path)
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+}
+
+func TestOptions_ParseUsingTOML(t *testing.T) {
+	t.Run("TOML config files will be used", func(t *testing.T) {
+		var config struct {
+			file    string
+			numeric int
+			host    string
+			ports   []uint
+			id      int64
+			started string
+		}
+
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"--config", filepath.Join("testdata", "config.toml")})
+		c := goconfigure.NewOption(&config.file, "config")
+		c.LongFlag("config")
+		opts.Add(c)
+
+		for key, p := range map[string]interface{}{
+			"numeric":  &config.numeric,
+			"db.host":  &config.host,
+			"db.ports": &config.ports,
+			"db.id":    &config.id,
+			"started":  &config.started,
+		} {
+			opt := goconfigure.NewOption(p, key)
+			opt.ConfigKey(key)
+			opts.Add(opt)
+		}
+
+		if err := opts.ParseUsing(c); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+
+		s := fmt.Sprintf("%v %v %v %v %v", config.numeric, config.host,
+			config.ports, config.id, config.started)
+		const expected = "4 localhost [80 443] 9007199254740993 " +
+			"1979-05-27T07:32:00Z"
+		if s != expected {
+			t.Errorf("unexpected config: %s", s)
+		}
+	})
+
+	t.Run("Invalid TOML will error with a line number", func(t *testing.T) {
+		var config string
+
+		path := filepath.Join("testdata", "invalid.toml")
+		opts := goconfigure.NewOptionsWithArgs([]string{"--config", path})
+		opt := goconfigure.NewOption(&config, "config")
+		opt.LongFlag("config")
+		opts.Add(opt)
+		err := opts.ParseUsing(opt)
+
+		prefix := fmt.Sprintf("error parsing config %s: toml: line 2", path)
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("unexpected error parsing options: %v", err)
+		}
+	})
+}
+
 func TestOptions_NArg(t *testing.T) {
 	t.Run("NArgs doesn't fail if Parse hasn't been called", func(t *testing.T) {
 		opts := goconfigure.NewOptionsWithArgs([]string{"command"})
@@ -309,7 +640,7 @@ func TestOptions_NArg(t *testing.T) {
 		a := opts.Args()
 
 		if len(a) != 0 {
-			t.Errorf("Unexpected Args: %d", a)
+			t.Errorf("Unexpected Args: %s", a)
 		}
 	})
 
@@ -347,6 +678,66 @@ func TestOptions_Usage(t *testing.T) {
 	})
 }
 
+func TestOptions_Explain(t *testing.T) {
+	t.Run("Explain handles no options", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		s := opts.Explain()
+
+		if !strings.Contains(s, "No configuration options set") {
+			t.Errorf("Unexpted explain output: %s", s)
+		}
+	})
+
+	t.Run("Explain handles unset options", func(t *testing.T) {
+		var value string
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opts.Add(goconfigure.NewOption(&value, "unset"))
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("Unexpected error parsing options: %s", err)
+		}
+
+		if s := opts.Explain(); !strings.Contains(s, "Not set") {
+			t.Errorf("Unexpted explain output: %s", s)
+		}
+	})
+
+	t.Run("Explain shows environment variables", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_EXPLAIN"
+		_ = os.Setenv(name, "1")
+		var value int
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&value, "env")
+		opt.EnvVar(name)
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("Unexpected error parsing options: %s", err)
+		}
+
+		s := opts.Explain()
+		if !strings.Contains(s, "Set to 1 by environment variable $"+name) {
+			t.Errorf("Unexpted explain output: %s", s)
+		}
+	})
+
+	t.Run("Explain shows single character long flags", func(t *testing.T) {
+		var value bool
+		opts := goconfigure.NewOptionsWithArgs([]string{"--v"})
+		opt := goconfigure.NewOption(&value, "verbose")
+		opt.LongFlag("v")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("Unexpected error parsing options: %s", err)
+		}
+
+		if s := opts.Explain(); !strings.Contains(s, "by flag --v") {
+			t.Errorf("Unexpted explain output: %s", s)
+		}
+	})
+}
+
 func TestOptions_UsageString(t *testing.T) {
 	t.Run("Usage handles no options", func(t *testing.T) {
 		opts := goconfigure.NewOptions()
@@ -0,0 +1,213 @@
+package goconfigure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+	"io/fs"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+func (o *options) OnChange(f func(option Option, previous,
+	current value.Data)) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.onChange = append(o.onChange, f)
+}
+
+func (o *options) OnError(f func(err error)) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.onError = append(o.onError, f)
+}
+
+func (o *options) WatchInterval(interval time.Duration) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.interval = interval
+}
+
+func (o *options) Watch(ctx context.Context) error {
+	o.lock.Lock()
+	files := len(o.files) + len(o.envFiles)
+	interval := o.interval
+	o.lock.Unlock()
+
+	if files == 0 {
+		return errors.New("no config files to watch")
+	}
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if o.modified() {
+				o.report(o.Reload())
+			}
+		}
+	}
+}
+
+func (o *options) Reload() error {
+	changes, err := o.reload()
+
+	if err != nil {
+		return err
+	}
+
+	o.notify(changes)
+	return nil
+}
+
+func (o *options) ReloadOnSignal(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+
+	go func() {
+		defer signal.Stop(c)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c:
+				o.report(o.Reload())
+			}
+		}
+	}()
+}
+
+// report the error to any OnError functions.
+func (o *options) report(err error) {
+	if err == nil {
+		return
+	}
+
+	o.lock.Lock()
+	f := append(o.onError[:0:0], o.onError...)
+	o.lock.Unlock()
+
+	for _, onError := range f {
+		onError(err)
+	}
+}
+
+// reload the options from the sources used when they were parsed, returning
+// the options whose value changed.
+func (o *options) reload() ([]change, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.source == nil {
+		return nil, errors.New("config error: options have not been parsed")
+	}
+
+	src, err := o.gather()
+
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := o.parseConfig(src)
+
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
+	return changes, nil
+}
+
+// change records the previous and current value of an option.
+type change struct {
+	option            Option
+	previous, current value.Data
+}
+
+// changes returns the options whose value differs between the previous and
+// updated resolutions. The lock must be held when calling changes.
+func (o *options) changes(previous, updated []resolution) []change {
+	var changes []change
+
+	for i, opt := range o.data {
+		p, c := previous[i].value, updated[i].value
+
+		if c.Pointer() == nil || reflect.DeepEqual(p.Pointer(), c.Pointer()) {
+			continue
+		}
+
+		changes = append(changes, change{option: opt, previous: p, current: c})
+	}
+
+	return changes
+}
+
+// notify the OnChange functions of the changes. The lock must not be held when
+// calling notify, allowing the OnChange functions to use the Options.
+func (o *options) notify(changes []change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	o.lock.Lock()
+	f := append(o.onChange[:0:0], o.onChange...)
+	o.lock.Unlock()
+
+	for _, c := range changes {
+		for _, onChange := range f {
+			onChange(c.option, c.previous, c.current)
+		}
+	}
+}
+
+// modified returns true if the files have changed since they were last loaded.
+func (o *options) modified() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.fingerprint() != o.loaded
+}
+
+// fingerprint returns a string that changes whenever any of the config files
+// or dotenv files are modified, created, or removed. The lock must be held
+// when calling fingerprint.
+func (o *options) fingerprint() string {
+	return fingerprintFiles(o.files, o.statConfig) +
+		fingerprintFiles(o.envFiles, os.Stat)
+}
+
+// fingerprintFiles returns a string that changes whenever any of the files
+// are modified, created, or removed.
+func fingerprintFiles(files []string,
+	stat func(name string) (fs.FileInfo, error)) string {
+	var s []interface{}
+
+	for _, file := range files {
+		if info, err := stat(file); err == nil {
+			s = append(s, file, info.ModTime().UnixNano(), info.Size())
+		} else {
+			s = append(s, file, "missing")
+		}
+	}
+
+	return fmt.Sprint(s...)
+}
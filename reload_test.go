@@ -0,0 +1,485 @@
+package goconfigure_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"github.com/domdavis/goconfigure/value"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOptions_Watch(t *testing.T) {
+	t.Run("Watch requires config files", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		if err := opts.Watch(context.Background()); err == nil {
+			t.Error("expected error watching without config files")
+		}
+	})
+
+	t.Run("Watch returns when the context is done", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{}`)
+
+		var config string
+		opts := goconfigure.NewOptionsWithArgs([]string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := opts.Watch(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Changes will be reloaded", func(t *testing.T) {
+		var config, message string
+		var count int
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"message": "hello", "count": 1}`)
+
+		opts, changes, errs := watched(t, []string{"-c", file, "--count", "2"})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		msg := goconfigure.NewOption(&message, "message")
+		msg.ConfigKey("message")
+		opts.Add(msg)
+		cnt := goconfigure.NewOption(&count, "count")
+		cnt.LongFlag("count")
+		cnt.ConfigKey("count")
+		opts.Add(cnt)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		cancel := watch(t, opts)
+		defer cancel()
+
+		write(t, file, `{"message": "goodbye", "count": 10}`)
+
+		if c, err := next(t, changes, errs); c != "hello -> goodbye" {
+			t.Errorf("unexpected change: %s, %v", c, err)
+		}
+
+		select {
+		case c := <-changes:
+			t.Errorf("unexpected change: %s", c)
+		case err := <-errs:
+			t.Errorf("unexpected error: %s", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if message != "goodbye" || count != 2 {
+			t.Errorf("unexpected values: %s %d", message, count)
+		}
+	})
+
+	t.Run("Removed keys will be reset", func(t *testing.T) {
+		var config, message string
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"message": "hello"}`)
+
+		opts, changes, errs := watched(t, []string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		msg := goconfigure.NewOption(&message, "message")
+		msg.ConfigKey("message")
+		opts.Add(msg)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		cancel := watch(t, opts)
+		defer cancel()
+
+		write(t, file, `{}`)
+
+		if c, err := next(t, changes, errs); c != "hello -> " {
+			t.Errorf("unexpected change: %s, %v", c, err)
+		}
+
+		if message != "" || opts.Snapshot().String("message") != "" {
+			t.Errorf("unexpected value: %s", message)
+		}
+	})
+
+	t.Run("Invalid changes will be ignored", func(t *testing.T) {
+		var config, message string
+		var count int
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"message": "hello", "count": 1}`)
+
+		opts, changes, errs := watched(t, []string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		msg := goconfigure.NewOption(&message, "message")
+		msg.ConfigKey("message")
+		opts.Add(msg)
+		cnt := goconfigure.NewOption(&count, "count")
+		cnt.ConfigKey("count")
+		cnt.Validate(goconfigure.Max(5))
+		opts.Add(cnt)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		cancel := watch(t, opts)
+		defer cancel()
+
+		write(t, file, `{"message": "goodbye", "count": 10}`)
+
+		if c, err := next(t, changes, errs); err == nil {
+			t.Errorf("unexpected change: %s", c)
+		}
+
+		if message != "hello" || count != 1 {
+			t.Errorf("unexpected values: %s %d", message, count)
+		}
+
+		write(t, file, `{"message": "hello", "count": 1, "broken": `)
+
+		if c, err := next(t, changes, errs); err == nil {
+			t.Errorf("unexpected change: %s", c)
+		}
+
+		if message != "hello" || count != 1 {
+			t.Errorf("unexpected values: %s %d", message, count)
+		}
+	})
+}
+
+func TestOptions_Reload(t *testing.T) {
+	t.Run("Options must be parsed before reloading", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		if err := opts.Reload(); err == nil {
+			t.Error("expected error reloading unparsed options")
+		}
+	})
+
+	t.Run("Environment variables will be read again", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_RELOAD"
+		var message string
+
+		_ = os.Setenv(name, "hello")
+		defer func() { _ = os.Unsetenv(name) }()
+
+		opts, changes, errs := watched(t, nil)
+		opt := goconfigure.NewOption(&message, "message")
+		opt.EnvVar(name)
+		opt.ConfigKey("message")
+		opts.Add(opt)
+
+		err := opts.Parse(map[string]interface{}{"message": "config"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		_ = os.Unsetenv(name)
+
+		if err = opts.Reload(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if c, err := next(t, changes, errs); c != "hello -> config" {
+			t.Errorf("unexpected change: %s, %v", c, err)
+		}
+	})
+
+	t.Run("OnChange functions can use the Options", func(t *testing.T) {
+		var message string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&message, "message")
+		opt.ConfigKey("message")
+		opts.Add(opt)
+		opts.OnChange(func(goconfigure.Option, value.Data, value.Data) {
+			if err := opts.WriteConfig(io.Discard, "json"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+
+		config := map[string]interface{}{"message": "hello"}
+
+		if err := opts.Parse(config); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		config["message"] = "goodbye"
+		done := make(chan error, 1)
+
+		go func() { done <- opts.Reload() }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for Reload")
+		}
+	})
+
+	t.Run("Removed keys will be reset", func(t *testing.T) {
+		var count int
+		var changed []string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&count, "count")
+		opt.ConfigKey("count")
+		opts.Add(opt)
+		opts.OnChange(func(_ goconfigure.Option, previous, current value.Data) {
+			changed = append(changed, fmt.Sprintf("%v -> %v",
+				previous.Pointer(), current.Pointer()))
+		})
+
+		config := map[string]interface{}{"count": 5}
+
+		if err := opts.Parse(config); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		delete(config, "count")
+
+		if err := opts.Reload(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if count != 0 || opts.Snapshot().Int("count") != 0 {
+			t.Errorf("unexpected value: %d", count)
+		}
+
+		if len(changed) != 1 || changed[0] != "5 -> 0" {
+			t.Errorf("unexpected changes: %v", changed)
+		}
+
+		if s := opt.Explain(); !strings.Contains(s, "Not set") {
+			t.Errorf("unexpected explanation: %s", s)
+		}
+
+		if err := opts.Reload(); err != nil || len(changed) != 1 {
+			t.Errorf("unexpected reload: %v, %v", err, changed)
+		}
+	})
+
+	t.Run("Failed reloads keep the previous values", func(t *testing.T) {
+		var first, second string
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"first": "a", "second": "b"}`)
+
+		var config string
+		opts, changes, _ := watched(t, []string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		f := goconfigure.NewOption(&first, "first")
+		f.ConfigKey("first")
+		opts.Add(f)
+		s := goconfigure.NewOption(&second, "second")
+		s.ConfigKey("second")
+		s.Validate(goconfigure.OneOf("b", "c"))
+		opts.Add(s)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		write(t, file, `{"first": "changed", "second": "invalid"}`)
+
+		if err := opts.Reload(); err == nil {
+			t.Error("expected error reloading invalid config")
+		}
+
+		if first != "a" || second != "b" || len(changes) != 0 {
+			t.Errorf("unexpected values: %s %s", first, second)
+		}
+	})
+
+	t.Run("Failed parses restore the option values", func(t *testing.T) {
+		count := 5
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"--count", "1"})
+		opt := goconfigure.NewOption(&count, "count")
+		opt.LongFlag("count")
+		opt.ConfigKey("count")
+		opt.Validate(goconfigure.Min(3))
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err == nil {
+			t.Error("expected error parsing invalid flag")
+		}
+
+		if v := opt.Value(); count != 5 || v.Source.Origin != value.None {
+			t.Errorf("unexpected value: %d %s", count, v.Source)
+		}
+
+		opts = goconfigure.NewOptionsWithArgs(nil)
+		opts.Add(opt)
+		config := map[string]interface{}{"count": 4}
+
+		if err := opts.Parse(config); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		config["count"] = 2
+
+		if err := opts.Reload(); err == nil {
+			t.Error("expected error reloading invalid config")
+		}
+
+		v := opt.Value()
+		if count != 4 || v.Pointer() != 4 || v.Source.Origin != value.Config {
+			t.Errorf("unexpected value: %d %v %s", count, v, v.Source)
+		}
+	})
+}
+
+func TestOptions_ReloadOnSignal(t *testing.T) {
+	t.Run("Config will be reloaded on SIGHUP", func(t *testing.T) {
+		var config, message string
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"message": "hello"}`)
+
+		opts, changes, errs := watched(t, []string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		msg := goconfigure.NewOption(&message, "message")
+		msg.ConfigKey("message")
+		opts.Add(msg)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts.ReloadOnSignal(ctx)
+		write(t, file, `{"message": "goodbye"}`)
+		hangup(t)
+
+		if c, err := next(t, changes, errs); c != "hello -> goodbye" {
+			t.Errorf("unexpected change: %s, %v", c, err)
+		}
+
+		write(t, file, `{"message": `)
+		hangup(t)
+
+		if c, err := next(t, changes, errs); err == nil {
+			t.Errorf("unexpected change: %s", c)
+		}
+
+		if message != "goodbye" {
+			t.Errorf("unexpected value: %s", message)
+		}
+	})
+}
+
+// watched returns a set of Options that reports changes to string options,
+// and any errors, on the returned channels.
+func watched(t *testing.T, args []string) (goconfigure.Options, chan string,
+	chan error) {
+	t.Helper()
+
+	changes := make(chan string, 10)
+	errs := make(chan error, 10)
+	opts := goconfigure.NewOptionsWithArgs(args)
+	opts.WatchInterval(5 * time.Millisecond)
+	opts.OnChange(func(_ goconfigure.Option, previous, current value.Data) {
+		p, ok := previous.Pointer().(string)
+
+		if ok {
+			changes <- p + " -> " + current.Pointer().(string)
+		}
+	})
+	opts.OnError(func(err error) { errs <- err })
+
+	return opts, changes, errs
+}
+
+// watch runs Watch in the background, returning a function that stops it.
+func watch(t *testing.T, opts goconfigure.Options) func() {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		_ = opts.Watch(ctx)
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// next returns the next change, or error, reported by a set of Options
+// returned by watched, failing the test if neither is reported in time.
+func next(t *testing.T, changes chan string, errs chan error) (string,
+	error) {
+	t.Helper()
+
+	select {
+	case c := <-changes:
+		return c, nil
+	case err := <-errs:
+		return "", err
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change")
+	}
+
+	return "", nil
+}
+
+func hangup(t *testing.T) {
+	t.Helper()
+
+	p, err := os.FindProcess(os.Getpid())
+
+	if err == nil {
+		err = p.Signal(syscall.SIGHUP)
+	}
+
+	if err != nil {
+		t.Fatalf("failed to send SIGHUP: %s", err)
+	}
+}
+
+func write(t *testing.T, file, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %s", file, err)
+	}
+}
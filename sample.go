@@ -0,0 +1,352 @@
+package goconfigure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/domdavis/goconfigure/value"
+	"gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// entry is a single option in a sample config file.
+type entry struct {
+	spec
+	path  []string
+	value interface{}
+}
+
+// tree holds the entries of a sample config file in the order they were added,
+// grouped by the nested object they belong to.
+type tree struct {
+	name     string
+	entries  []entry
+	children []*tree
+}
+
+func (o *options) WriteSample(w io.Writer, format string) error {
+	if format == "" {
+		format = "json"
+	}
+
+	root, err := o.sample()
+
+	if err != nil {
+		return fmt.Errorf("failed to write sample config: %s", err)
+	}
+
+	var b []byte
+
+	switch format := strings.ToLower(strings.TrimPrefix(format, ".")); format {
+	case "yaml", "yml":
+		b, err = root.yaml()
+	case "toml":
+		b, err = root.toml()
+	default:
+		e, ok := encoder(format)
+
+		if !ok {
+			return fmt.Errorf("no encoder registered for format '%s'", format)
+		}
+
+		b, err = e.Encode(root.config())
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write sample config: %s", err)
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func (o *options) WriteSampleDescriptions(w io.Writer) error {
+	root, err := o.sample()
+
+	if err != nil {
+		return fmt.Errorf("failed to write sample descriptions: %s", err)
+	}
+
+	type description struct {
+		Description string      `json:"description,omitempty"`
+		Type        string      `json:"type"`
+		Default     interface{} `json:"default,omitempty"`
+		Required    bool        `json:"required,omitempty"`
+	}
+
+	d := map[string]description{}
+
+	for _, e := range root.all() {
+		d[joinKey(e.path)] = description{
+			Description: e.description,
+			Type:        e.typeOf.String(),
+			Default:     e.defaultValue(),
+			Required:    e.required,
+		}
+	}
+
+	b, err := json.MarshalIndent(d, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("failed to write sample descriptions: %s", err)
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// sample builds the tree of options with config keys, checking for conflicting
+// keys.
+func (o *options) sample() (*tree, error) {
+	root := &tree{}
+	check := map[string]interface{}{}
+
+	for _, opt := range o.data {
+		s, ok := opt.(specifier)
+
+		if !ok || s.spec().key == "" || s.spec().typeOf == nil {
+			continue
+		}
+
+		e := entry{spec: s.spec(), path: splitKey(s.spec().key)}
+		e.value = e.defaultValue()
+
+		if e.value == nil {
+			e.value = plain(zero(e.typeOf))
+		}
+
+		if err := insert(check, e.key, e.value); err != nil {
+			return nil, err
+		}
+
+		root.add(e, e.path[:len(e.path)-1])
+	}
+
+	return root, nil
+}
+
+// zero returns the zero value for t, using empty rather than nil slices and
+// maps so they are written as empty arrays and objects.
+func zero(t reflect.Type) interface{} {
+	switch t.Kind() {
+	case reflect.Slice:
+		return reflect.MakeSlice(t, 0, 0).Interface()
+	case reflect.Map:
+		return reflect.MakeMap(t).Interface()
+	}
+
+	return reflect.Zero(t).Interface()
+}
+
+// defaultValue returns the default value of the entry as it would be written
+// in a config file, or nil if it has no default or is sensitive.
+func (e entry) defaultValue() interface{} {
+	if e.backstop == nil || e.sensitive {
+		return nil
+	}
+
+	return plain(e.backstop)
+}
+
+// comment describes the entry.
+func (e entry) comment() string {
+	var details []string
+
+	details = append(details, e.typeOf.String())
+
+	if e.required {
+		details = append(details, "required")
+	}
+
+	if e.defaultValue() != nil {
+		details = append(details, "default "+display(value.New(e.backstop)))
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", e.description,
+		strings.Join(details, ", ")))
+}
+
+func (t *tree) add(e entry, parents []string) {
+	if len(parents) == 0 {
+		t.entries = append(t.entries, e)
+		return
+	}
+
+	for _, child := range t.children {
+		if child.name == parents[0] {
+			child.add(e, parents[1:])
+			return
+		}
+	}
+
+	child := &tree{name: parents[0]}
+	t.children = append(t.children, child)
+	child.add(e, parents[1:])
+}
+
+func (t *tree) all() []entry {
+	entries := append([]entry{}, t.entries...)
+
+	for _, child := range t.children {
+		entries = append(entries, child.all()...)
+	}
+
+	return entries
+}
+
+func (t *tree) config() map[string]interface{} {
+	config := map[string]interface{}{}
+
+	for _, e := range t.all() {
+		_ = insert(config, e.key, e.value)
+	}
+
+	return config
+}
+
+func (t *tree) yaml() ([]byte, error) {
+	n, err := t.node()
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := bytes.Buffer{}
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	if err = enc.Encode(n); err == nil {
+		err = enc.Close()
+	}
+
+	return b.Bytes(), err
+}
+
+func (t *tree) node() (*yaml.Node, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, e := range t.entries {
+		v := &yaml.Node{}
+
+		if err := v.Encode(e.value); err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content, &yaml.Node{
+			Kind:        yaml.ScalarNode,
+			Value:       e.path[len(e.path)-1],
+			HeadComment: e.comment(),
+		}, v)
+	}
+
+	for _, child := range t.children {
+		v, err := child.node()
+
+		if err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: child.name}, v)
+	}
+
+	return n, nil
+}
+
+func (t *tree) toml() ([]byte, error) {
+	b := &bytes.Buffer{}
+	err := t.writeTOML(b, nil)
+	return b.Bytes(), err
+}
+
+func (t *tree) writeTOML(b *bytes.Buffer, path []string) error {
+	if len(path) > 0 && len(t.entries) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		keys := make([]string, len(path))
+
+		for i, element := range path {
+			keys[i] = tomlKey(element)
+		}
+
+		b.WriteString("[" + strings.Join(keys, ".") + "]\n")
+	}
+
+	for _, e := range t.entries {
+		if err := checkTOML(e.path, e.value); err != nil {
+			return err
+		}
+
+		v, err := tomlValue(e.value)
+
+		if err != nil {
+			return err
+		}
+
+		for _, line := range strings.Split(e.comment(), "\n") {
+			b.WriteString("# " + line + "\n")
+		}
+
+		b.WriteString(tomlKey(e.path[len(e.path)-1]) + " = " + v + "\n")
+	}
+
+	for _, child := range t.children {
+		p := append(append([]string{}, path...), child.name)
+
+		if err := child.writeTOML(b, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+
+	b, _ := json.Marshal(key)
+	return string(b)
+}
+
+// tomlValue returns v as a TOML value, using an inline table for maps.
+func tomlValue(v interface{}) (string, error) {
+	r := reflect.ValueOf(v)
+
+	if r.Kind() == reflect.Map {
+		var entries []string
+
+		for i := r.MapRange(); i.Next(); {
+			e, err := tomlValue(i.Value().Interface())
+
+			if err != nil {
+				return "", err
+			}
+
+			entries = append(entries, tomlKey(i.Key().String())+" = "+e)
+		}
+
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	}
+
+	b := bytes.Buffer{}
+
+	err := toml.NewEncoder(&b).Encode(map[string]interface{}{"v": v})
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(b.String(), "v = ")), nil
+}
@@ -0,0 +1,203 @@
+package goconfigure_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func ExampleOptions_WriteSample() {
+	var message string
+	var count int
+	var timeout time.Duration
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	opt := goconfigure.NewOption(&message, "The message to display")
+	opt.ConfigKey("message")
+	opt.Default("Hello, world!")
+	opts.Add(opt)
+	opt = goconfigure.NewOption(&count, "The number of times to display it")
+	opt.ConfigKey("count")
+	opt.Required()
+	opts.Add(opt)
+	opt = goconfigure.NewOption(&timeout, "The request timeout")
+	opt.ConfigKey("server.timeout")
+	opt.Default(time.Second)
+	opts.Add(opt)
+
+	if err := opts.WriteSample(os.Stdout, "yaml"); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// # The message to display (string, default "Hello, world!")
+	// message: Hello, world!
+	// # The number of times to display it (int, required)
+	// count: 0
+	// server:
+	//   # The request timeout (time.Duration, default 1s)
+	//   timeout: 1000000000
+}
+
+func TestOptions_WriteSample(t *testing.T) {
+	t.Run("Unknown formats will error", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		err := opts.WriteSample(&bytes.Buffer{}, "ini")
+
+		const expected = "no encoder registered for format 'ini'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Conflicting keys will error", func(t *testing.T) {
+		var a, b string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&a, "a")
+		o.ConfigKey("a")
+		opts.Add(o)
+		o = goconfigure.NewOption(&b, "b")
+		o.ConfigKey("a.b")
+		opts.Add(o)
+
+		if err := opts.WriteSample(&bytes.Buffer{}, "toml"); err == nil {
+			t.Error("expected error for conflicting keys")
+		}
+
+		if err := opts.WriteSampleDescriptions(&bytes.Buffer{}); err == nil {
+			t.Error("expected error for conflicting keys")
+		}
+	})
+
+	t.Run("Large defaults cannot be written as TOML", func(t *testing.T) {
+		var limits map[string]uint64
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&limits, "limits")
+		o.ConfigKey("limits")
+		o.Default(map[string]uint64{"max": math.MaxUint64})
+		opts.Add(o)
+
+		err := opts.WriteSample(&bytes.Buffer{}, "toml")
+
+		const expected = "failed to write sample config: value of config " +
+			"key 'limits.max' is too large for a TOML integer"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("TOML samples include comments", func(t *testing.T) {
+		var password string
+		var labels map[string]int
+		var hosts []string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&password, "The password")
+		o.ConfigKey("db.password")
+		o.Default("hunter2")
+		o.Sensitive()
+		opts.Add(o)
+		o = goconfigure.NewOption(&labels, "Labels")
+		o.ConfigKey("labels")
+		o.Default(map[string]int{"a.b": 1, "c": 2})
+		opts.Add(o)
+		o = goconfigure.NewOption(&hosts, "Hosts")
+		o.ConfigKey("db.hosts")
+		o.Default([]string{"a", "b"})
+		opts.Add(o)
+		b := &bytes.Buffer{}
+
+		if err := opts.WriteSample(b, ".TOML"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		const expected = `# Labels (map[string]int, default map[a.b:1 c:2])
+labels = {"a.b" = 1, c = 2}
+
+[db]
+# The password (string)
+password = ""
+# Hosts ([]string, default [a b])
+hosts = ["a", "b"]
+`
+		if b.String() != expected {
+			t.Errorf("unexpected sample: %s", b)
+		}
+	})
+
+	for _, format := range []string{"json", "toml", "yaml"} {
+		t.Run("Samples can be loaded as "+format, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "config."+format)
+			sample := &roundTrip{}
+			opts := sample.options(nil)
+
+			f, err := os.Create(file)
+
+			if err == nil {
+				err = opts.WriteSample(f, format)
+				_ = f.Close()
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			in := &roundTrip{}
+			opts = in.options([]string{"-c", file})
+
+			if err = opts.ParseUsing(in.config); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+
+			got := fmt.Sprint(in.list, in.labels, in.timeout, in.max)
+			if got != "[] map[] 0s 0" {
+				t.Errorf("unexpected config: %s", got)
+			}
+		})
+	}
+}
+
+func TestOptions_WriteSampleDescriptions(t *testing.T) {
+	var count int
+	var token string
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	o := goconfigure.NewOption(&count, "The count")
+	o.ConfigKey("nested.count")
+	o.Default(3)
+	o.Required()
+	opts.Add(o)
+	o = goconfigure.NewOption(&token, "")
+	o.ConfigKey("token")
+	o.Default("secret")
+	o.Sensitive()
+	opts.Add(o)
+	b := &bytes.Buffer{}
+
+	if err := opts.WriteSampleDescriptions(b); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	const expected = `{
+  "nested.count": {
+    "description": "The count",
+    "type": "int",
+    "default": 3,
+    "required": true
+  },
+  "token": {
+    "type": "string"
+  }
+}
+`
+	if b.String() != expected {
+		t.Errorf("unexpected descriptions: %s", b)
+	}
+}
@@ -0,0 +1,163 @@
+package goconfigure
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// schemaVersion is the JSON Schema dialect produced by JSONSchema.
+const schemaVersion = "https://json-schema.org/draft/2020-12/schema"
+
+func (o *options) JSONSchema() ([]byte, error) {
+	root, err := o.sample()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schema: %s", err)
+	}
+
+	s := root.schema(o.strict)
+	s["$schema"] = schemaVersion
+
+	b, err := json.MarshalIndent(s, "", "  ")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schema: %s", err)
+	}
+
+	return append(b, '\n'), nil
+}
+
+// schema returns the schema for the object described by the tree.
+func (t *tree) schema(strict bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	for _, e := range t.entries {
+		name := e.path[len(e.path)-1]
+		properties[name] = e.schema()
+
+		if e.required {
+			required = append(required, name)
+		}
+	}
+
+	for _, child := range t.children {
+		properties[child.name] = child.schema(strict)
+
+		if child.required() {
+			required = append(required, child.name)
+		}
+	}
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+
+	if len(required) > 0 {
+		s["required"] = required
+	}
+
+	if strict {
+		s["additionalProperties"] = false
+	}
+
+	return s
+}
+
+// required returns true if the tree holds a required entry at any depth.
+func (t *tree) required() bool {
+	for _, e := range t.entries {
+		if e.required {
+			return true
+		}
+	}
+
+	for _, child := range t.children {
+		if child.required() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// schema returns the schema for the value of the entry.
+func (e entry) schema() map[string]interface{} {
+	s := typeSchema(e.typeOf)
+
+	if e.description != "" {
+		s["description"] = e.description
+	}
+
+	if d := e.defaultValue(); d != nil {
+		s["default"] = d
+	}
+
+	if e.sensitive {
+		s["writeOnly"] = true
+	}
+
+	// Validators apply to each element of slices and maps.
+	element := s
+
+	switch e.typeOf.Kind() {
+	case reflect.Slice:
+		element = s["items"].(map[string]interface{})
+	case reflect.Map:
+		element = s["additionalProperties"].(map[string]interface{})
+	}
+
+	for _, v := range e.validators {
+		switch v := v.(type) {
+		case *bound:
+			if v.min {
+				element["minimum"] = plain(v.limit)
+			} else {
+				element["maximum"] = plain(v.limit)
+			}
+		case *pattern:
+			if element["type"] == "string" {
+				element["pattern"] = v.re.String()
+			}
+		case *oneOf:
+			values := make([]interface{}, len(v.values))
+
+			for i, value := range v.values {
+				values[i] = plain(value)
+			}
+
+			element["enum"] = values
+		}
+	}
+
+	return s
+}
+
+// typeSchema returns the schema for values of type t. Durations are given in
+// nanoseconds, as they are in config files.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{"type": "integer"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array",
+			"items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object",
+			"additionalProperties": typeSchema(t.Elem())}
+	}
+
+	return map[string]interface{}{}
+}
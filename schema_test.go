@@ -0,0 +1,236 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"testing"
+	"time"
+)
+
+func ExampleOptions_JSONSchema() {
+	var host string
+	var port uint
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	opt := goconfigure.NewOption(&host, "The host to connect to")
+	opt.ConfigKey("server.host")
+	opt.Default("localhost")
+	opts.Add(opt)
+	opt = goconfigure.NewOption(&port, "The port to connect to")
+	opt.ConfigKey("server.port")
+	opt.Required()
+	opt.Validate(goconfigure.Max(65535))
+	opts.Add(opt)
+
+	b, err := opts.JSONSchema()
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Print(string(b))
+
+	// Output:
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "properties": {
+	//     "server": {
+	//       "properties": {
+	//         "host": {
+	//           "default": "localhost",
+	//           "description": "The host to connect to",
+	//           "type": "string"
+	//         },
+	//         "port": {
+	//           "description": "The port to connect to",
+	//           "maximum": 65535,
+	//           "minimum": 0,
+	//           "type": "integer"
+	//         }
+	//       },
+	//       "required": [
+	//         "port"
+	//       ],
+	//       "type": "object"
+	//     }
+	//   },
+	//   "required": [
+	//     "server"
+	//   ],
+	//   "type": "object"
+	// }
+}
+
+func TestOptions_JSONSchema(t *testing.T) {
+	t.Run("Conflicting keys will error", func(t *testing.T) {
+		var a, b string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&a, "a")
+		o.ConfigKey("a")
+		opts.Add(o)
+		o = goconfigure.NewOption(&b, "b")
+		o.ConfigKey("a.b")
+		opts.Add(o)
+
+		if _, err := opts.JSONSchema(); err == nil {
+			t.Error("expected error for conflicting keys")
+		}
+	})
+
+	t.Run("Strict schemas disallow unknown keys", func(t *testing.T) {
+		var enabled bool
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&enabled, "")
+		o.ConfigKey("feature.enabled")
+		opts.Add(o)
+		opts.Strict()
+
+		b, err := opts.JSONSchema()
+
+		const expected = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "additionalProperties": false,
+  "properties": {
+    "feature": {
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "type": "boolean"
+        }
+      },
+      "type": "object"
+    }
+  },
+  "type": "object"
+}
+`
+		if err != nil || string(b) != expected {
+			t.Errorf("unexpected schema: %s, %v", b, err)
+		}
+	})
+
+	t.Run("Objects holding required keys are required", func(t *testing.T) {
+		var host string
+		var port int
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&host, "")
+		o.ConfigKey("db.primary.host")
+		o.Required()
+		opts.Add(o)
+		o = goconfigure.NewOption(&port, "")
+		o.ConfigKey("server.port")
+		opts.Add(o)
+
+		b, err := opts.JSONSchema()
+
+		const expected = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "db": {
+      "properties": {
+        "primary": {
+          "properties": {
+            "host": {
+              "type": "string"
+            }
+          },
+          "required": [
+            "host"
+          ],
+          "type": "object"
+        }
+      },
+      "required": [
+        "primary"
+      ],
+      "type": "object"
+    },
+    "server": {
+      "properties": {
+        "port": {
+          "type": "integer"
+        }
+      },
+      "type": "object"
+    }
+  },
+  "required": [
+    "db"
+  ],
+  "type": "object"
+}
+`
+		if err != nil || string(b) != expected {
+			t.Errorf("unexpected schema: %s, %v", b, err)
+		}
+	})
+
+	t.Run("Validators apply to elements", func(t *testing.T) {
+		var names []string
+		var timeouts map[string]time.Duration
+		var ratio float64
+		var token string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&names, "")
+		o.ConfigKey("names")
+		o.Validate(goconfigure.Pattern("^[a-z]+$"),
+			goconfigure.OneOf("a", "b"))
+		opts.Add(o)
+		o = goconfigure.NewOption(&timeouts, "")
+		o.ConfigKey("timeouts")
+		o.Validate(goconfigure.Min(time.Second), goconfigure.Pattern("1"))
+		opts.Add(o)
+		o = goconfigure.NewOption(&ratio, "")
+		o.ConfigKey("ratio")
+		o.Validate(goconfigure.Min(0.5))
+		opts.Add(o)
+		o = goconfigure.NewOption(&token, "")
+		o.ConfigKey("token")
+		o.Default("secret")
+		o.Sensitive()
+		opts.Add(o)
+
+		b, err := opts.JSONSchema()
+
+		const expected = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "names": {
+      "items": {
+        "enum": [
+          "a",
+          "b"
+        ],
+        "pattern": "^[a-z]+$",
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "ratio": {
+      "minimum": 0.5,
+      "type": "number"
+    },
+    "timeouts": {
+      "additionalProperties": {
+        "minimum": 1000000000,
+        "type": "integer"
+      },
+      "type": "object"
+    },
+    "token": {
+      "type": "string",
+      "writeOnly": true
+    }
+  },
+  "type": "object"
+}
+`
+		if err != nil || string(b) != expected {
+			t.Errorf("unexpected schema: %s, %v", b, err)
+		}
+	})
+}
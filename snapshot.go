@@ -0,0 +1,145 @@
+package goconfigure
+
+import (
+	"github.com/domdavis/goconfigure/value"
+	"reflect"
+	"time"
+)
+
+// Snapshot is an immutable copy of the values of a set of Options, taken when
+// they were last successfully parsed or reloaded. Options are looked up by
+// name, which can be the config key, long flag, environment variable, or
+// short flag of the option. If more than one option shares a name then the
+// first option added is used. A Snapshot is safe for concurrent use.
+type Snapshot struct {
+	values map[string]value.Data
+}
+
+// identifier is implemented by options that can be looked up in a Snapshot.
+type identifier interface {
+	identifiers() []string
+}
+
+func newSnapshot(options []Option) *Snapshot {
+	s := &Snapshot{values: map[string]value.Data{}}
+
+	for _, opt := range options {
+		id, ok := opt.(identifier)
+
+		if !ok {
+			continue
+		}
+
+		v := opt.Value()
+
+		if p, ok := opt.(parser); ok {
+			v = p.current().value
+		}
+
+		v = clone(v)
+
+		for _, name := range id.identifiers() {
+			if _, exists := s.values[name]; !exists {
+				s.values[name] = v
+			}
+		}
+	}
+
+	return s
+}
+
+// Value returns a copy of the value of the named option, and whether the option
+// exists.
+func (s *Snapshot) Value(name string) (value.Data, bool) {
+	v, ok := s.values[name]
+	return clone(v), ok
+}
+
+// Lookup returns the value of the named option in the Snapshot as type T.
+// Lookup returns false if the option does not exist, has no value, or its
+// value is not of type T. Values are not converted, so looking up an int
+// option as a float64 returns false.
+func Lookup[T Type](s *Snapshot, name string) (T, bool) {
+	t, ok := clone(s.values[name]).Pointer().(T)
+	return t, ok
+}
+
+// Bool returns the value of the named option, or false if it is not a bool.
+func (s *Snapshot) Bool(name string) bool {
+	v, _ := Lookup[bool](s, name)
+	return v
+}
+
+// Int returns the value of the named option, or 0 if it is not an int.
+func (s *Snapshot) Int(name string) int {
+	v, _ := Lookup[int](s, name)
+	return v
+}
+
+// Int64 returns the value of the named option, or 0 if it is not an int64.
+func (s *Snapshot) Int64(name string) int64 {
+	v, _ := Lookup[int64](s, name)
+	return v
+}
+
+// Uint returns the value of the named option, or 0 if it is not a uint.
+func (s *Snapshot) Uint(name string) uint {
+	v, _ := Lookup[uint](s, name)
+	return v
+}
+
+// Uint64 returns the value of the named option, or 0 if it is not a uint64.
+func (s *Snapshot) Uint64(name string) uint64 {
+	v, _ := Lookup[uint64](s, name)
+	return v
+}
+
+// Float64 returns the value of the named option, or 0 if it is not a float64.
+func (s *Snapshot) Float64(name string) float64 {
+	v, _ := Lookup[float64](s, name)
+	return v
+}
+
+// String returns the value of the named option, or "" if it is not a string.
+func (s *Snapshot) String(name string) string {
+	v, _ := Lookup[string](s, name)
+	return v
+}
+
+// Duration returns the value of the named option, or 0 if it is not a
+// time.Duration.
+func (s *Snapshot) Duration(name string) time.Duration {
+	v, _ := Lookup[time.Duration](s, name)
+	return v
+}
+
+// Strings returns the value of the named option, or nil if it is not a
+// []string.
+func (s *Snapshot) Strings(name string) []string {
+	v, _ := Lookup[[]string](s, name)
+	return v
+}
+
+// clone returns a copy of the value held by v so slices and maps are not
+// shared.
+func clone(v value.Data) value.Data {
+	if v.Pointer() == nil {
+		return v
+	}
+
+	t := reflect.TypeOf(v.Pointer())
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	c := reflect.New(t)
+
+	if err := v.AssignTo(c.Interface()); err != nil {
+		return v
+	}
+
+	d := value.New(c.Elem().Interface()).From(v.Source)
+	d.Sensitive = v.Sensitive
+	return d
+}
@@ -0,0 +1,204 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func ExampleOptions_Snapshot() {
+	var host string
+	var port int
+
+	opts := goconfigure.NewOptionsWithArgs([]string{"--port", "80"})
+	h := goconfigure.NewOption(&host, "host")
+	h.ConfigKey("server.host")
+	opts.Add(h)
+	p := goconfigure.NewOption(&port, "port")
+	p.LongFlag("port")
+	p.Default(8080)
+	opts.Add(p)
+
+	err := opts.Parse(map[string]interface{}{
+		"server": map[string]interface{}{"host": "localhost"},
+	})
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	s := opts.Snapshot()
+	fmt.Println(s.String("server.host"), s.Int("port"))
+
+	// Output:
+	// localhost 80
+}
+
+func TestOptions_Snapshot(t *testing.T) {
+	t.Run("Snapshots are empty before parsing", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+
+		if _, ok := opts.Snapshot().Value("missing"); ok {
+			t.Error("unexpected value in empty snapshot")
+		}
+	})
+
+	t.Run("Unsupported option types will error", func(t *testing.T) {
+		var p *int
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&p, "pointer")
+		opt.ConfigKey("pointer")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err == nil {
+			t.Error("expected error parsing unsupported type")
+		}
+
+		if _, ok := opts.Snapshot().Value("pointer"); ok {
+			t.Error("unexpected value in snapshot")
+		}
+	})
+
+	t.Run("Options can be looked up by any name", func(t *testing.T) {
+		var message string
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"-m", "hello"})
+		opt := goconfigure.NewOption(&message, "message")
+		opt.Flags('m', "message")
+		opt.EnvVar("GOCONFIGURE_TEST_SNAPSHOT")
+		opt.ConfigKey("msg")
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		s := opts.Snapshot()
+
+		for _, name := range []string{
+			"m", "message", "GOCONFIGURE_TEST_SNAPSHOT", "msg"} {
+			if v := s.String(name); v != "hello" {
+				t.Errorf("unexpected value for %s: %q", name, v)
+			}
+		}
+
+		v, ok := s.Value("message")
+
+		if !ok || v.Source.String() != "flag -m" {
+			t.Errorf("unexpected value: %v", v)
+		}
+	})
+
+	t.Run("Typed getters return zero values on mismatch", func(t *testing.T) {
+		var count int
+		var timeout time.Duration
+		var enabled bool
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		c := goconfigure.NewOption(&count, "count")
+		c.ConfigKey("count")
+		c.Default(3)
+		opts.Add(c)
+		d := goconfigure.NewOption(&timeout, "timeout")
+		d.ConfigKey("timeout")
+		d.Default(time.Second)
+		opts.Add(d)
+		b := goconfigure.NewOption(&enabled, "enabled")
+		b.ConfigKey("enabled")
+		opts.Add(b)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		s := opts.Snapshot()
+		r := fmt.Sprintln(s.Int("count"), s.Int64("count"), s.Uint("count"),
+			s.Uint64("count"), s.Float64("count"), s.Duration("timeout"),
+			s.Bool("enabled"), s.String("count"), s.Bool("count"),
+			s.Strings("count"), s.Int("missing"))
+
+		if r != "3 0 0 0 0 1s false  false [] 0\n" {
+			t.Errorf("unexpected values: %s", r)
+		}
+
+		if _, ok := goconfigure.Lookup[string](s, "count"); ok {
+			t.Error("unexpected string value for int option")
+		}
+
+		if _, ok := goconfigure.Lookup[int64](s, "timeout"); ok {
+			t.Error("unexpected int64 value for duration option")
+		}
+	})
+
+	t.Run("Snapshots cannot be modified", func(t *testing.T) {
+		var list []string
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"-l", "a", "-l", "b"})
+		opt := goconfigure.NewOption(&list, "list")
+		opt.ShortFlag('l')
+		opts.Add(opt)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		s := opts.Snapshot()
+		list[0] = "changed"
+		s.Strings("l")[1] = "changed"
+
+		if v := s.Strings("l"); fmt.Sprint(v) != "[a b]" {
+			t.Errorf("unexpected value: %v", v)
+		}
+	})
+
+	t.Run("Reloads replace the snapshot", func(t *testing.T) {
+		var config string
+		var count int
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"count": 1}`)
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		c := goconfigure.NewOption(&count, "count")
+		c.ConfigKey("count")
+		opts.Add(c)
+
+		if err := opts.ParseUsing(opt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		before := opts.Snapshot()
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				if v := opts.Snapshot().Int("count"); v != 1 && v != 2 {
+					t.Errorf("unexpected value: %d", v)
+				}
+			}
+		}()
+
+		write(t, file, `{"count": 2}`)
+
+		if err := opts.Reload(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		wg.Wait()
+
+		if before.Int("count") != 1 || opts.Snapshot().Int("count") != 2 {
+			t.Errorf("unexpected values: %d %d", before.Int("count"),
+				opts.Snapshot().Int("count"))
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package goconfigure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (o *options) Strict() {
+	o.strict = true
+}
+
+// unknown returns an error for each key in the config that is not used by any
+// option.
+func (o *options) unknown(src *sources) []error {
+	var keys []string
+
+	for _, opt := range o.data {
+		if s, ok := opt.(specifier); ok && s.spec().key != "" {
+			keys = append(keys, joinKey(splitKey(s.spec().key)))
+		}
+	}
+
+	var errs []error
+
+	for _, key := range unclaimed(src.config, nil, keys) {
+		b := strings.Builder{}
+		b.WriteString(fmt.Sprintf("unknown config key '%s'", key))
+
+		if file := src.file(key); file != "" {
+			b.WriteString(" in " + file)
+		}
+
+		if s, ok := suggest(key, keys); ok {
+			b.WriteString(fmt.Sprintf(", did you mean '%s'?", s))
+		}
+
+		errs = append(errs, fmt.Errorf("%s", b.String()))
+	}
+
+	return errs
+}
+
+// unclaimed returns the keys in config, in order, that are neither one of the
+// given keys nor an object containing one of them.
+func unclaimed(config map[string]interface{}, path, keys []string) []string {
+	var names []string
+	var found []string
+
+	for k := range config {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := append(append([]string{}, path...), name)
+		key := joinKey(p)
+		claimed, parent := false, false
+
+		for _, k := range keys {
+			claimed = claimed || k == key
+			parent = parent || strings.HasPrefix(k, key+".")
+		}
+
+		m, isObject := config[name].(map[string]interface{})
+
+		switch {
+		case claimed:
+		case parent && isObject:
+			found = append(found, unclaimed(m, p, keys)...)
+		default:
+			found = append(found, key)
+		}
+	}
+
+	return found
+}
+
+// suggest returns the key closest to the given key, if any are close enough to
+// be a likely typo.
+func suggest(key string, keys []string) (string, bool) {
+	best, min := "", -1
+
+	for _, k := range keys {
+		if d := distance(key, k); min < 0 || d < min {
+			best, min = k, d
+		}
+	}
+
+	return best, min >= 0 && min <= (len([]rune(best))+1)/2
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	row := make([]int, len(t)+1)
+
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		prev := row[0]
+		row[0] = i
+
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			next := row[j]
+			row[j] = minimum(row[j]+1, row[j-1]+1, prev+cost)
+			prev = next
+		}
+	}
+
+	return row[len(t)]
+}
+
+func minimum(values ...int) int {
+	m := values[0]
+
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
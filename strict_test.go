@@ -0,0 +1,130 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"path/filepath"
+	"testing"
+)
+
+func ExampleOptions_Strict() {
+	var count int
+
+	opts := goconfigure.NewOptionsWithArgs(nil)
+	opt := goconfigure.NewOption(&count, "count")
+	opt.ConfigKey("count")
+	opts.Add(opt)
+	opts.Strict()
+
+	err := opts.Parse(map[string]interface{}{"cout": 3})
+
+	fmt.Println(err)
+
+	// Output:
+	// config error: error parsing options: unknown config key 'cout', did you mean 'count'?
+}
+
+func TestOptions_Strict(t *testing.T) {
+	t.Run("Known keys will be accepted", func(t *testing.T) {
+		var host string
+		var labels map[string]string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&host, "host")
+		opt.ConfigKey("db.host")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&labels, "labels")
+		opt.ConfigKey(`example\.com.labels`)
+		opts.Add(opt)
+		opts.Strict()
+
+		err := opts.Parse(map[string]interface{}{
+			"db": map[string]interface{}{"host": "localhost"},
+			"example.com": map[string]interface{}{
+				"labels": map[string]interface{}{"a": "b"},
+			},
+		})
+
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Every unknown key will be reported", func(t *testing.T) {
+		var host string
+		var port int
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&host, "host")
+		opt.ConfigKey("db.host")
+		opts.Add(opt)
+		opt = goconfigure.NewOption(&port, "port")
+		opt.ConfigKey("db.port")
+		opts.Add(opt)
+		opts.Strict()
+
+		err := opts.Parse(map[string]interface{}{
+			"db": map[string]interface{}{
+				"hots":    "localhost",
+				"port":    5432,
+				"options": map[string]interface{}{"ssl": true},
+			},
+			"verbose": true,
+		})
+
+		const expected = "config error: error parsing options: " +
+			"unknown config key 'db.hots', did you mean 'db.host'?; " +
+			"unknown config key 'db.options'; " +
+			"unknown config key 'verbose'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if host != "" || port != 0 {
+			t.Errorf("unexpected values: %s %d", host, port)
+		}
+	})
+
+	t.Run("Unknown keys are attributed to files", func(t *testing.T) {
+		var config string
+		var count int
+
+		file := filepath.Join(t.TempDir(), "config.json")
+		write(t, file, `{"count": 1, "cont": 2}`)
+
+		opts := goconfigure.NewOptionsWithArgs([]string{"-c", file})
+		opt := goconfigure.NewOption(&config, "config file")
+		opt.ShortFlag('c')
+		opts.Add(opt)
+		cnt := goconfigure.NewOption(&count, "count")
+		cnt.ConfigKey("count")
+		opts.Add(cnt)
+		opts.Strict()
+
+		err := opts.ParseUsing(opt)
+
+		expected := fmt.Sprintf("config error: error parsing options: "+
+			"unknown config key 'cont' in %s, did you mean 'count'?", file)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Distant keys have no suggestion", func(t *testing.T) {
+		var count int
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&count, "count")
+		opt.ConfigKey("count")
+		opts.Add(opt)
+		opts.Strict()
+
+		err := opts.Parse(map[string]interface{}{"timeout": 3})
+
+		const expected = "config error: error parsing options: " +
+			"unknown config key 'timeout'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
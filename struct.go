@@ -0,0 +1,179 @@
+package goconfigure
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// FromStruct returns a new Options type, taking its flags from the arguments
+// provided to the process, with an Option for each tagged field of the struct
+// pointed to by i. The following tags are used:
+//
+//     flag:"m,message"  short and/or long flags for the option
+//     env:"MESSAGE"     the environment variable for the option
+//     config:"message"  the config key for the option
+//     default:"hello"   the default value for the option
+//     desc:"A message"  the description used in usage information
+//     sep:":"           the separator used for slices and maps
+//
+// Single character flags are treated as short flags and anything longer as a
+// long flag. Defaults are given as they would be in an environment variable.
+// Exported fields with none of these tags are ignored, with the exception of
+// nested structs with exported fields, which are walked unless they have a
+// tag other than config. It is an error for a tagged field to have a type an
+// Option cannot hold. If a nested struct has a config tag then it is used as
+// the parent object for the config keys of its fields, so:
+//
+//     var cfg struct {
+//         DB struct {
+//             Host string `config:"host"`
+//         } `config:"db"`
+//     }
+//
+// will set cfg.DB.Host using the config key "db.host".
+func FromStruct(i interface{}) (Options, error) {
+	return FromStructWithArgs(i, os.Args[1:])
+}
+
+// FromStructWithArgs returns a new Options type for the struct pointed to by i,
+// as with FromStruct, using the given slice of strings as its argument set.
+func FromStructWithArgs(i interface{}, args []string) (Options, error) {
+	v := reflect.ValueOf(i)
+
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FromStruct requires a pointer to a struct, "+
+			"not %T", i)
+	}
+
+	opts := NewOptionsWithArgs(args)
+
+	if err := addStruct(opts, v.Elem(), ""); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+var structTags = []string{"flag", "env", "config", "default", "desc", "sep"}
+
+func addStruct(opts Options, v reflect.Value, prefix string) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key, hasKey := field.Tag.Lookup("config")
+
+		if hasKey && prefix != "" {
+			key = prefix + "." + key
+		} else if !hasKey {
+			key = prefix
+		}
+
+		if nested(field) {
+			if err := addStruct(opts, v.Field(i), key); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !tagged(field.Tag, structTags...) {
+			continue
+		}
+
+		if !value.Supported(v.Field(i).Addr().Interface()) {
+			return fmt.Errorf("invalid field %s: unsupported type %s",
+				field.Name, field.Type)
+		}
+
+		opt, err := fieldOption(field, v.Field(i).Addr().Interface(), key,
+			hasKey)
+
+		if err != nil {
+			return fmt.Errorf("invalid field %s: %s", field.Name, err)
+		}
+
+		opts.Add(opt)
+	}
+
+	return nil
+}
+
+func fieldOption(field reflect.StructField, pointer interface{}, key string,
+	hasKey bool) (Option, error) {
+	o := NewOption(pointer, field.Tag.Get("desc")).(*option)
+
+	if flags, ok := field.Tag.Lookup("flag"); ok {
+		for _, name := range strings.Split(flags, ",") {
+			name = strings.TrimSpace(name)
+
+			switch utf8.RuneCountInString(name) {
+			case 0:
+			case 1:
+				r, _ := utf8.DecodeRuneInString(name)
+				o.ShortFlag(r)
+			default:
+				o.LongFlag(name)
+			}
+		}
+	}
+
+	if env, ok := field.Tag.Lookup("env"); ok {
+		o.EnvVar(env)
+	}
+
+	if hasKey {
+		o.ConfigKey(key)
+	}
+
+	if sep, ok := field.Tag.Lookup("sep"); ok {
+		o.Separator(sep)
+	}
+
+	if d, ok := field.Tag.Lookup("default"); ok {
+		v, err := o.coerce(d)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid default: %s", err)
+		}
+
+		o.Default(v.Pointer())
+	}
+
+	return o, nil
+}
+
+// nested returns true if the field is a struct to be walked for more options.
+func nested(field reflect.StructField) bool {
+	if field.Type.Kind() != reflect.Struct ||
+		tagged(field.Tag, "flag", "env", "default", "desc", "sep") {
+		return false
+	}
+
+	for i := 0; i < field.Type.NumField(); i++ {
+		if field.Type.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tagged(tag reflect.StructTag, names ...string) bool {
+	for _, name := range names {
+		if _, ok := tag.Lookup(name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"os"
+	"testing"
+	"time"
+)
+
+func ExampleFromStruct() {
+	var cfg struct {
+		Message string `flag:"m,message" config:"message" default:"hello"`
+		Count   int    `flag:"count" env:"UNSET_ENV_VAR" default:"1"`
+		DB      struct {
+			Host string `config:"host" desc:"database host"`
+		} `config:"db"`
+	}
+
+	opts, err := goconfigure.FromStruct(&cfg)
+
+	// We need to replace opts so arguments sent be testing harnesses are not
+	// included. Ordinarily this line can be omitted.
+	opts, err = goconfigure.FromStructWithArgs(&cfg, []string{"--count", "2"})
+
+	if err == nil {
+		err = opts.Parse(map[string]interface{}{
+			"db": map[string]interface{}{"host": "localhost"},
+		})
+	}
+
+	if err == nil {
+		fmt.Println(cfg.Message, cfg.Count, cfg.DB.Host)
+	} else {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// hello 2 localhost
+}
+
+func TestFromStruct(t *testing.T) {
+	t.Run("Non struct pointers will error", func(t *testing.T) {
+		var cfg struct{}
+		_, err := goconfigure.FromStructWithArgs(cfg, nil)
+
+		expected := "FromStruct requires a pointer to a struct, not struct {}"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Invalid defaults will error", func(t *testing.T) {
+		var cfg struct {
+			Count int `default:"one"`
+		}
+
+		_, err := goconfigure.FromStructWithArgs(&cfg, nil)
+
+		const expected = "invalid field Count: invalid default: value.Data: " +
+			"cannot coerce 'one': strconv.ParseInt: parsing \"one\": " +
+			"invalid syntax"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Errors in nested structs are returned", func(t *testing.T) {
+		var cfg struct {
+			Nested struct {
+				Count int `default:"one"`
+			}
+		}
+
+		if _, err := goconfigure.FromStructWithArgs(&cfg, nil); err == nil {
+			t.Error("expected error from nested struct")
+		}
+	})
+
+	t.Run("Unsupported field types will error", func(t *testing.T) {
+		var cfg struct {
+			Start time.Time `config:"start"`
+		}
+
+		_, err := goconfigure.FromStructWithArgs(&cfg, nil)
+
+		const expected = "invalid field Start: unsupported type time.Time"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Tagged structs will not be walked", func(t *testing.T) {
+		var cfg struct {
+			Nested struct {
+				Count int `config:"count"`
+			} `config:"nested" flag:"nested"`
+		}
+
+		if _, err := goconfigure.FromStructWithArgs(&cfg, nil); err == nil {
+			t.Error("expected error for tagged struct")
+		}
+	})
+
+	t.Run("Tags will be used", func(t *testing.T) {
+		const name = "GOCONFIGURE_TEST_STRUCT"
+		_ = os.Setenv(name, "a:b")
+
+		var cfg struct {
+			Short   bool           `flag:"s"`
+			Long    time.Duration  `flag:"long"`
+			List    []string       `env:"GOCONFIGURE_TEST_STRUCT" sep:":"`
+			Labels  map[string]int `default:"a=1,b=2"`
+			Ignored string
+			Server  struct {
+				Port uint `config:"port"`
+			} `config:"server"`
+			Flattened struct {
+				Name string `config:"name"`
+			}
+			unexported string `flag:"unexported"`
+		}
+
+		opts, err := goconfigure.FromStructWithArgs(&cfg, []string{
+			"-s", "--long", "1m"})
+
+		if err == nil {
+			err = opts.Parse(map[string]interface{}{
+				"server":  map[string]interface{}{"port": float64(80)},
+				"name":    "flat",
+				"Ignored": "ignored",
+			})
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		s := fmt.Sprintln(cfg.Short, cfg.Long, cfg.List, cfg.Labels, cfg.Ignored,
+			cfg.Server.Port, cfg.Flattened.Name, cfg.unexported)
+		if s != "true 1m0s [a b] map[a:1 b:2]  80 flat \n" {
+			t.Errorf("unexpected config: %s", s)
+		}
+	})
+}
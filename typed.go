@@ -0,0 +1,50 @@
+package goconfigure
+
+import (
+	"github.com/domdavis/goconfigure/value"
+	"time"
+)
+
+// Type is the set of types that can be held by a Typed option.
+type Type interface {
+	bool | int | int64 | uint | uint64 | float64 | string | value.Secret |
+		time.Duration |
+		[]bool | []int | []int64 | []uint | []uint64 | []float64 | []string |
+		[]time.Duration | map[string]bool | map[string]int |
+		map[string]int64 | map[string]uint | map[string]uint64 |
+		map[string]float64 | map[string]string | map[string]time.Duration
+}
+
+// Typed is an Option that holds its own value of type T. Unlike Option the
+// default value is typed, so providing a default of the wrong type is a compile
+// time error. The embedded Option is used to define the flags, environment
+// variable, and config key for the option, and to add it to a set of Options:
+//
+//     port := goconfigure.New[int]("The port to listen on")
+//     port.Flags('p', "port")
+//     port.Default(8080)
+//     opts.Add(port.Option)
+type Typed[T Type] struct {
+	Option
+	value T
+}
+
+// New returns a Typed option holding a value of type T. The description is used
+// when producing usage information.
+func New[T Type](description string) *Typed[T] {
+	t := &Typed[T]{}
+	t.Option = NewOption(&t.value, description)
+	return t
+}
+
+// Default defines a value that will be used by the option if no flags,
+// environment variables, or configuration file values are set or found.
+func (t *Typed[T]) Default(value T) {
+	t.Option.Default(value)
+}
+
+// Get returns the value held by this option. Get will return the zero value for
+// T until the option has been parsed.
+func (t *Typed[T]) Get() T {
+	return t.value
+}
@@ -0,0 +1,65 @@
+package goconfigure_test
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"testing"
+	"time"
+)
+
+func ExampleNew() {
+	opts := goconfigure.NewOptionsWithArgs([]string{"--port", "80"})
+
+	port := goconfigure.New[int]("The port to listen on")
+	port.Flags('p', "port")
+	port.Default(8080)
+	opts.Add(port.Option)
+
+	timeout := goconfigure.New[time.Duration]("The request timeout")
+	timeout.ConfigKey("timeout")
+	timeout.Default(time.Second)
+	opts.Add(timeout.Option)
+
+	if err := opts.Parse(nil); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(port.Get(), timeout.Get())
+
+	// Output:
+	// 80 1s
+}
+
+func TestTyped_Get(t *testing.T) {
+	t.Run("Get returns the zero value before parsing", func(t *testing.T) {
+		opt := goconfigure.New[string]("test")
+		opt.Default("default")
+
+		if v := opt.Get(); v != "" {
+			t.Errorf("unexpected value before parsing: %q", v)
+		}
+	})
+
+	t.Run("Slice and map types are supported", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs([]string{
+			"-l", "a=1", "-n", "1", "-n", "2"})
+
+		labels := goconfigure.New[map[string]int]("labels")
+		labels.ShortFlag('l')
+		opts.Add(labels.Option)
+
+		numbers := goconfigure.New[[]uint]("numbers")
+		numbers.ShortFlag('n')
+		numbers.Default([]uint{3})
+		opts.Add(numbers.Option)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Errorf("unexpected error parsing options: %s", err)
+		}
+
+		s := fmt.Sprint(labels.Get(), numbers.Get())
+		if s != "map[a:1] [1 2]" {
+			t.Errorf("unexpected values: %s", s)
+		}
+	})
+}
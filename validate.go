@@ -0,0 +1,198 @@
+package goconfigure
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Validator checks the value of an Option once the value to use has been
+// chosen from the flags, environment variable, config file, and default.
+// Validators that also implement fmt.Stringer have their description included
+// in the usage information for the Option. Errors returned by a Validator
+// should not include the value, as it may be sensitive.
+type Validator interface {
+	Validate(v value.Data) error
+}
+
+// ValidatorFunc allows an ordinary function to be used as a Validator.
+type ValidatorFunc func(v value.Data) error
+
+// Validate calls f(v).
+func (f ValidatorFunc) Validate(v value.Data) error {
+	return f(v)
+}
+
+// Min returns a Validator that requires numeric and time.Duration values to be
+// at least min. For slices and maps each element is checked.
+func Min(min interface{}) Validator {
+	return &bound{limit: min, min: true}
+}
+
+// Max returns a Validator that requires numeric and time.Duration values to be
+// at most max. For slices and maps each element is checked.
+func Max(max interface{}) Validator {
+	return &bound{limit: max}
+}
+
+// Pattern returns a Validator that requires values to match the regular
+// expression expr. Non-string values are matched using their default format.
+// For slices and maps each element is checked. Pattern panics if expr cannot
+// be compiled.
+func Pattern(expr string) Validator {
+	return &pattern{re: regexp.MustCompile(expr)}
+}
+
+// OneOf returns a Validator that requires values to be one of the given
+// values. For slices and maps each element is checked.
+func OneOf(values ...interface{}) Validator {
+	return &oneOf{values: values}
+}
+
+type bound struct {
+	limit interface{}
+	min   bool
+}
+
+func (b *bound) Validate(v value.Data) error {
+	limit, ok := number(reflect.ValueOf(b.limit))
+
+	if !ok {
+		return fmt.Errorf("cannot compare with %v (%[1]T)", b.limit)
+	}
+
+	return each(v, func(e reflect.Value) error {
+		n, ok := number(e)
+
+		switch {
+		case !ok:
+			return fmt.Errorf("cannot compare %s with %v", e.Type(),
+				b.limit)
+		case b.min && n < limit, !b.min && n > limit:
+			return fmt.Errorf("must be %s", b)
+		}
+
+		return nil
+	})
+}
+
+func (b *bound) String() string {
+	if b.min {
+		return fmt.Sprintf("at least %v", b.limit)
+	}
+
+	return fmt.Sprintf("at most %v", b.limit)
+}
+
+type pattern struct {
+	re *regexp.Regexp
+}
+
+func (p *pattern) Validate(v value.Data) error {
+	return each(v, func(e reflect.Value) error {
+		if !p.re.MatchString(text(e)) {
+			return fmt.Errorf("must be %s", p)
+		}
+
+		return nil
+	})
+}
+
+func (p *pattern) String() string {
+	return fmt.Sprintf("matching %s", p.re)
+}
+
+type oneOf struct {
+	values []interface{}
+}
+
+func (o *oneOf) Validate(v value.Data) error {
+	return each(v, func(e reflect.Value) error {
+		s := text(e)
+
+		for _, allowed := range o.values {
+			if text(reflect.ValueOf(allowed)) == s {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be %s", o)
+	})
+}
+
+func (o *oneOf) String() string {
+	s := make([]string, len(o.values))
+
+	for i, v := range o.values {
+		if _, ok := v.(string); ok {
+			s[i] = fmt.Sprintf("%q", v)
+		} else {
+			s[i] = fmt.Sprint(v)
+		}
+	}
+
+	return "one of " + strings.Join(s, ", ")
+}
+
+// each calls f with the value held by v, or with each element of the value if
+// it is a slice or map.
+func each(v value.Data, f func(e reflect.Value) error) error {
+	r := reflect.ValueOf(v.Pointer())
+
+	if r.Kind() == reflect.Ptr {
+		r = r.Elem()
+	}
+
+	switch r.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice:
+		for i := 0; i < r.Len(); i++ {
+			if err := f(r.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for i := r.MapRange(); i.Next(); {
+			if err := f(i.Value()); err != nil {
+				return fmt.Errorf("'%s' %s", i.Key(), err)
+			}
+		}
+	default:
+		return f(r)
+	}
+
+	return nil
+}
+
+// text returns the value held by v as a string. Strings, including
+// value.Secret, are used as is rather than being formatted, so the contents of
+// a Secret are checked rather than its mask.
+func text(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return fmt.Sprint(nil)
+	case reflect.String:
+		return v.String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// number returns the numeric value of v, if it has one.
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
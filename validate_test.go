@@ -0,0 +1,168 @@
+package goconfigure_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"github.com/domdavis/goconfigure/value"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ExampleOption_Validate() {
+	var port int
+
+	opts := goconfigure.NewOptionsWithArgs([]string{"--port", "70000"})
+	opt := goconfigure.NewOption(&port, "port")
+	opt.LongFlag("port")
+	opt.Validate(goconfigure.Min(1), goconfigure.Max(65535))
+	opts.Add(opt)
+
+	fmt.Println(opts.Parse(nil))
+
+	// Output:
+	// config error: error parsing options: invalid value 70000 for 'port' from flag --port: must be at most 65535
+}
+
+func TestValidators(t *testing.T) {
+	parse := func(i interface{}, config interface{},
+		validators ...goconfigure.Validator) error {
+		opt := goconfigure.NewOption(i, "test")
+		opt.ConfigKey("key")
+		opt.Validate(validators...)
+		return opt.Parse(map[string]interface{}{"key": config})
+	}
+
+	for name, test := range map[string]struct {
+		pointer   interface{}
+		config    interface{}
+		validator goconfigure.Validator
+		expected  string
+	}{
+		"Min passes": {new(int), float64(1), goconfigure.Min(1), ""},
+		"Min fails": {new(int), float64(0), goconfigure.Min(1),
+			"must be at least 1"},
+		"Max passes": {new(float64), 1.5, goconfigure.Max(1.5), ""},
+		"Max fails": {new(float64), 1.6, goconfigure.Max(1.5),
+			"must be at most 1.5"},
+		"Durations can be bound": {new(time.Duration), float64(time.Minute),
+			goconfigure.Max(time.Second), "must be at most 1s"},
+		"Strings can't be bound": {new(string), "text", goconfigure.Min(1),
+			"cannot compare string with 1"},
+		"Bounds must be numeric": {new(int), float64(1), goconfigure.Min("1"),
+			"cannot compare with 1 (string)"},
+		"Pattern passes": {new(string), "abc", goconfigure.Pattern("^a"), ""},
+		"Pattern fails": {new(string), "cba", goconfigure.Pattern("^a"),
+			"must be matching ^a"},
+		"OneOf passes": {new(string), "fast",
+			goconfigure.OneOf("fast", "safe"), ""},
+		"OneOf fails": {new(string), "slow", goconfigure.OneOf("fast", "safe"),
+			`must be one of "fast", "safe"`},
+		"Slice elements are checked": {new([]uint),
+			[]interface{}{float64(1), float64(3)}, goconfigure.OneOf(1, 2),
+			"must be one of 1, 2"},
+		"Map values are checked": {new(map[string]int),
+			map[string]interface{}{"a": float64(3)}, goconfigure.Max(2),
+			"'a' must be at most 2"},
+		"Functions can be used": {new(int), float64(1),
+			goconfigure.ValidatorFunc(func(v value.Data) error {
+				return errors.New("invalid " + v.Source.Name)
+			}), "invalid key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := parse(test.pointer, test.config, test.validator)
+
+			switch {
+			case test.expected == "" && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case test.expected != "" &&
+				(err == nil || !strings.HasSuffix(err.Error(), test.expected)):
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("Failing values are not assigned", func(t *testing.T) {
+		value := 5
+		err := parse(&value, float64(0), goconfigure.Min(1))
+
+		expected := "invalid value 0 for 'test' from config key 'key': " +
+			"must be at least 1"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		} else if value != 5 {
+			t.Errorf("unexpected value: %d", value)
+		}
+	})
+
+	t.Run("Defaults are validated", func(t *testing.T) {
+		var value string
+		opt := goconfigure.NewOption(&value, "test")
+		opt.Default("slow")
+		opt.Validate(goconfigure.OneOf("fast", "safe"))
+		err := opt.Parse(nil)
+
+		expected := "invalid value \"slow\" for 'test' from default: " +
+			"must be one of \"fast\", \"safe\""
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Unset values are not validated", func(t *testing.T) {
+		var value int
+		opt := goconfigure.NewOption(&value, "test")
+		opt.Validate(goconfigure.Min(1))
+
+		if err := opt.Parse(nil); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Secrets are validated using their contents", func(t *testing.T) {
+		var password value.Secret
+
+		for args, valid := range map[string]bool{
+			"--pw hunter2":  true,
+			"--pw short":    false,
+			"--pw guessme!": false,
+		} {
+			opts := goconfigure.NewOptionsWithArgs(strings.Fields(args))
+			opt := goconfigure.NewOption(&password, "password")
+			opt.LongFlag("pw")
+			opt.Validate(goconfigure.Pattern("^.{7,}$"),
+				goconfigure.OneOf(value.Secret("hunter2"), "letmein"))
+			opts.Add(opt)
+
+			if err := opts.Parse(nil); (err == nil) != valid {
+				t.Errorf("unexpected result for %s: %v", args, err)
+			}
+		}
+	})
+
+	t.Run("Invalid patterns panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic from invalid pattern")
+			}
+		}()
+
+		goconfigure.Pattern("(")
+	})
+}
+
+func TestValidators_String(t *testing.T) {
+	t.Run("Validators are shown in usage", func(t *testing.T) {
+		opt := goconfigure.NewOption(new(string), "mode")
+		opt.Validate(goconfigure.OneOf("fast", "safe"),
+			goconfigure.Pattern("^[a-z]+$"),
+			goconfigure.ValidatorFunc(func(value.Data) error { return nil }))
+		s := opt.String()
+
+		expected := `Values must be one of "fast", "safe" and matching ^[a-z]+$.`
+		if !strings.Contains(s, expected) {
+			t.Errorf("unexpected usage string:\n%s", s)
+		}
+	})
+}
@@ -4,22 +4,81 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Data holds an untyped (interface{}) value which can be assigned to a bool,
-// int, int64, uint, uint64, float64, string, or time.Duration.
+// int, int64, uint, uint64, float64, string, Secret, or time.Duration, or a
+// slice or string keyed map of any of these types other than Secret.
 type Data struct {
-	Set     bool
+	Set    bool
+	Source Source
+
+	// Sensitive values are replaced with Redacted when the Data type is
+	// formatted, and are not included in errors.
+	Sensitive bool
+
 	pointer interface{}
 }
 
+// Origin identifies the kind of source a value came from.
+type Origin int
+
+// The origins a value can come from.
+const (
+	None Origin = iota
+	Default
+	Flag
+	EnvVar
+	Config
+)
+
+// Source records where the value held by a Data type came from.
+type Source struct {
+	// Origin is the kind of source the value came from.
+	Origin Origin
+
+	// Name is the flag, environment variable, or config key the value was
+	// taken from. Flags include their leading dashes, so short and long flags
+	// with the same name can be told apart.
+	Name string
+
+	// File is the configuration file the value was loaded from, if known. For
+	// environment variables it is the dotenv file the variable was loaded
+	// from, or the file named by a variable with a _FILE suffix.
+	File string
+}
+
+// String returns a human readable description of the source.
+func (s Source) String() string {
+	switch {
+	case s.Origin == Default:
+		return "default"
+	case s.Origin == Flag:
+		return "flag " + s.Name
+	case s.Origin == EnvVar && s.File != "":
+		return fmt.Sprintf("environment variable $%s (%s)", s.Name, s.File)
+	case s.Origin == EnvVar:
+		return "environment variable $" + s.Name
+	case s.Origin == Config && s.File != "":
+		return fmt.Sprintf("config key '%s' in %s", s.Name, s.File)
+	case s.Origin == Config:
+		return fmt.Sprintf("config key '%s'", s.Name)
+	}
+
+	return "unset"
+}
+
 // New creates a new Data type with the given value.
 func New(data interface{}) Data {
 	return Data{Set: true, pointer: data}
 }
 
-// Coerce the given string into a Data type holding a value of typeOf.
+// Coerce the given string into a Data type holding a value of typeOf. If typeOf
+// is a pointer to a slice then the string is coerced into a single element
+// slice. If typeOf is a pointer to a map then the string must be of the form
+// key=value and is coerced into a single entry map.
 func Coerce(data string, typeOf interface{}) (Data, error) {
 	var r interface{}
 	var err error
@@ -41,8 +100,15 @@ func Coerce(data string, typeOf interface{}) (Data, error) {
 		r, err = strconv.ParseFloat(data, 64)
 	case *string:
 		r = data
+	case *Secret:
+		r = Secret(data)
 	case *time.Duration:
 		r, err = time.ParseDuration(data)
+	case *[]bool, *[]int, *[]int64, *[]uint, *[]uint64, *[]float64, *[]string,
+		*[]time.Duration, *map[string]bool, *map[string]int,
+		*map[string]int64, *map[string]uint, *map[string]uint64,
+		*map[string]float64, *map[string]string, *map[string]time.Duration:
+		return CoerceAll([]string{data}, typeOf)
 	default:
 		err = fmt.Errorf("invalid type: %T", typeOf)
 	}
@@ -54,6 +120,88 @@ func Coerce(data string, typeOf interface{}) (Data, error) {
 	return New(r), err
 }
 
+// CoerceAll coerces each of the given strings into an element of the slice or
+// map type pointed to by typeOf, returning a Data type holding the resulting
+// slice or map. Map elements must be of the form key=value.
+func CoerceAll(data []string, typeOf interface{}) (Data, error) {
+	switch typeOf.(type) {
+	case *[]bool, *[]int, *[]int64, *[]uint, *[]uint64, *[]float64, *[]string,
+		*[]time.Duration:
+	case *map[string]bool, *map[string]int, *map[string]int64,
+		*map[string]uint, *map[string]uint64, *map[string]float64,
+		*map[string]string, *map[string]time.Duration:
+		return coerceMap(data, reflect.TypeOf(typeOf).Elem())
+	default:
+		return New(nil), fmt.Errorf("value.Data: cannot coerce %q: "+
+			"invalid type: %T", data, typeOf)
+	}
+
+	t := reflect.TypeOf(typeOf).Elem()
+	s := reflect.MakeSlice(t, 0, len(data))
+
+	for _, d := range data {
+		v, err := Coerce(d, reflect.New(t.Elem()).Interface())
+
+		if err != nil {
+			return New(nil), err
+		}
+
+		s = reflect.Append(s, reflect.ValueOf(v.pointer))
+	}
+
+	return New(s.Interface()), nil
+}
+
+// Supported returns true if typeOf is a pointer to a type that a Data type can
+// be coerced to and assigned to.
+func Supported(typeOf interface{}) bool {
+	switch typeOf.(type) {
+	case *bool, *int, *int64, *uint, *uint64, *float64, *string, *Secret,
+		*time.Duration, *[]bool, *[]int, *[]int64, *[]uint, *[]uint64,
+		*[]float64, *[]string, *[]time.Duration, *map[string]bool,
+		*map[string]int, *map[string]int64, *map[string]uint,
+		*map[string]uint64, *map[string]float64, *map[string]string,
+		*map[string]time.Duration:
+		return true
+	}
+
+	return false
+}
+
+// From returns a copy of this Data type with its source set to the given
+// Source.
+func (d Data) From(source Source) Data {
+	d.Source = source
+	return d
+}
+
+// String returns the value held by this Data type, and its source if known.
+// The value is replaced with Redacted if the Data type is Sensitive.
+func (d Data) String() string {
+	var v interface{} = Redacted
+	r := reflect.ValueOf(d.pointer)
+
+	switch {
+	case d.Sensitive:
+	case r.Kind() == reflect.Ptr && !r.IsNil():
+		v = r.Elem().Interface()
+	default:
+		v = d.pointer
+	}
+
+	if d.Source.Origin == None {
+		return fmt.Sprint(v)
+	}
+
+	return fmt.Sprintf("%v (from %s)", v, d.Source)
+}
+
+// GoString returns the same as String, ensuring sensitive values are not
+// revealed when formatted with %#v.
+func (d Data) GoString() string {
+	return d.String()
+}
+
 // Pointer returns the underlying value wrapped by this data type.
 func (d Data) Pointer() interface{} {
 	return d.pointer
@@ -62,6 +210,12 @@ func (d Data) Pointer() interface{} {
 // AssignTo sets the given pointer to point to the value held by this Data type.
 func (d Data) AssignTo(pointer interface{}) (err error) {
 	var to, from reflect.Value
+	defer func() {
+		if err != nil && d.Sensitive {
+			err = fmt.Errorf("value.Data %s, failed to assign to type %v",
+				Redacted, reflect.Indirect(reflect.ValueOf(pointer)).Type())
+		}
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("value.Data %v '%v', failed to assign to type %v",
@@ -77,7 +231,11 @@ func (d Data) AssignTo(pointer interface{}) (err error) {
 	to = reflect.ValueOf(pointer)
 
 	if from.Kind() == reflect.Ptr {
-		from = reflect.Indirect(from)
+		if from.IsNil() {
+			return nil
+		}
+
+		from = from.Elem()
 	}
 
 	if to.Kind() == reflect.Ptr {
@@ -86,6 +244,16 @@ func (d Data) AssignTo(pointer interface{}) (err error) {
 		return fmt.Errorf("cannot assign to %v, should be *%[1]v", to.Type())
 	}
 
+	switch pointer.(type) {
+	case *[]bool, *[]int, *[]int64, *[]uint, *[]uint64, *[]float64, *[]string,
+		*[]time.Duration:
+		return assignSlice(from, to)
+	case *map[string]bool, *map[string]int, *map[string]int64,
+		*map[string]uint, *map[string]uint64, *map[string]float64,
+		*map[string]string, *map[string]time.Duration:
+		return assignMap(from, to)
+	}
+
 	data := from.Convert(to.Type())
 
 	switch p := pointer.(type) {
@@ -107,6 +275,12 @@ func (d Data) AssignTo(pointer interface{}) (err error) {
 				from.Kind())
 		}
 		*p = data.String()
+	case *Secret:
+		if from.Kind() != reflect.String {
+			return fmt.Errorf("value.Data: invalid cast of %v to Secret",
+				from.Kind())
+		}
+		*p = Secret(data.String())
 	case *time.Duration:
 		*p = time.Duration(data.Int())
 	default:
@@ -115,3 +289,80 @@ func (d Data) AssignTo(pointer interface{}) (err error) {
 
 	return nil
 }
+
+func coerceMap(data []string, t reflect.Type) (Data, error) {
+	m := reflect.MakeMapWithSize(t, len(data))
+
+	for _, d := range data {
+		kv := strings.SplitN(d, "=", 2)
+
+		if len(kv) != 2 {
+			return New(nil), fmt.Errorf("value.Data: cannot coerce '%s': "+
+				"expected key=value", d)
+		}
+
+		v, err := Coerce(kv[1], reflect.New(t.Elem()).Interface())
+
+		if err != nil {
+			return New(nil), err
+		}
+
+		m.SetMapIndex(reflect.ValueOf(kv[0]), reflect.ValueOf(v.pointer))
+	}
+
+	return New(m.Interface()), nil
+}
+
+func assignSlice(from, to reflect.Value) error {
+	if from.Kind() != reflect.Slice && from.Kind() != reflect.Array {
+		return fmt.Errorf("value.Data %v '%v', failed to assign to type %v",
+			from.Type(), from.Interface(), to.Type())
+	}
+
+	if from.Kind() == reflect.Slice && from.IsNil() {
+		to.Set(reflect.Zero(to.Type()))
+		return nil
+	}
+
+	s := reflect.MakeSlice(to.Type(), from.Len(), from.Len())
+
+	for i := 0; i < from.Len(); i++ {
+		d := New(from.Index(i).Interface())
+
+		if err := d.AssignTo(s.Index(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("value.Data: element %d: %s", i, err)
+		}
+	}
+
+	to.Set(s)
+	return nil
+}
+
+func assignMap(from, to reflect.Value) error {
+	if from.Kind() != reflect.Map || from.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("value.Data %v '%v', failed to assign to type %v",
+			from.Type(), from.Interface(), to.Type())
+	}
+
+	if from.IsNil() {
+		to.Set(reflect.Zero(to.Type()))
+		return nil
+	}
+
+	m := reflect.MakeMapWithSize(to.Type(), from.Len())
+	i := from.MapRange()
+
+	for i.Next() {
+		d := New(i.Value().Interface())
+		v := reflect.New(to.Type().Elem())
+
+		if err := d.AssignTo(v.Interface()); err != nil {
+			return fmt.Errorf("value.Data: key '%s': %s", i.Key(), err)
+		}
+
+		m.SetMapIndex(i.Key().Convert(to.Type().Key()), v.Elem())
+	}
+
+	to.Set(m)
+	return nil
+}
@@ -17,7 +17,7 @@ func ExampleCoerce() {
 	}
 
 	// Output:
-	// {true text}
+	// text
 }
 
 func ExampleData_AssignTo() {
@@ -33,6 +33,63 @@ func ExampleData_AssignTo() {
 	// example
 }
 
+func ExampleSource_String() {
+	d := value.New("example").From(value.Source{
+		Origin: value.Config, Name: "key", File: "config.json"})
+
+	fmt.Println(d.Source)
+	fmt.Println(d)
+
+	// Output:
+	// config key 'key' in config.json
+	// example (from config key 'key' in config.json)
+}
+
+func TestSource_String(t *testing.T) {
+	for expected, s := range map[string]value.Source{
+		"unset":                     {},
+		"default":                   {Origin: value.Default},
+		"flag -f":                   {Origin: value.Flag, Name: "-f"},
+		"flag --flag":               {Origin: value.Flag, Name: "--flag"},
+		"flag --f":                  {Origin: value.Flag, Name: "--f"},
+		"environment variable $ENV": {Origin: value.EnvVar, Name: "ENV"},
+		"config key 'key'":          {Origin: value.Config, Name: "key"},
+		"environment variable $ENV_FILE (/run/secrets/env)": {
+			Origin: value.EnvVar, Name: "ENV_FILE", File: "/run/secrets/env"},
+	} {
+		t.Run(expected, func(t *testing.T) {
+			if s.String() != expected {
+				t.Errorf("unexpected source string: %s", s)
+			}
+		})
+	}
+}
+
+func TestData_String(t *testing.T) {
+	t.Run("Pointers are dereferenced", func(t *testing.T) {
+		v := 1
+		d := value.New(&v).From(value.Source{Origin: value.Default})
+
+		if s := d.String(); s != "1 (from default)" {
+			t.Errorf("unexpected data string: %s", s)
+		}
+	})
+
+	t.Run("Sensitive values are redacted", func(t *testing.T) {
+		d := value.New("hunter2").From(value.Source{Origin: value.Default})
+		d.Sensitive = true
+
+		s := fmt.Sprintf("%s %v %+v %#v", d, d, d, d)
+		if strings.Contains(s, "hunter2") {
+			t.Errorf("unexpected data string: %s", s)
+		}
+
+		if s := d.String(); s != "******** (from default)" {
+			t.Errorf("unexpected data string: %s", s)
+		}
+	})
+}
+
 func TestCoerce(t *testing.T) {
 	t.Run("bool", func(t *testing.T) {
 		var p bool
@@ -106,12 +163,41 @@ func TestCoerce(t *testing.T) {
 		}
 	})
 
+	t.Run("slice", func(t *testing.T) {
+		var p []int
+		if v, err := value.Coerce("1", &p); err != nil {
+			t.Errorf("Unexpected error coercing slice: %s", err.Error())
+		} else if s, ok := v.Pointer().([]int); !ok || len(s) != 1 || s[0] != 1 {
+			t.Errorf("Unexpected value coercing slice: %v", v.Pointer())
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		var p map[string]int
+		if v, err := value.Coerce("key=1", &p); err != nil {
+			t.Errorf("Unexpected error coercing map: %s", err.Error())
+		} else if m, ok := v.Pointer().(map[string]int); !ok || len(m) != 1 ||
+			m["key"] != 1 {
+			t.Errorf("Unexpected value coercing map: %v", v.Pointer())
+		}
+	})
+
+	t.Run("map without value", func(t *testing.T) {
+		var p map[string]int
+		_, err := value.Coerce("key", &p)
+
+		expected := "value.Data: cannot coerce 'key': expected key=value"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error coercing map: %v", err)
+		}
+	})
+
 	t.Run("invalid type", func(t *testing.T) {
-		var p []string
+		var p []complex128
 		_, err := value.Coerce("invalid", &p)
 
 		expected := "value.Data: cannot coerce 'invalid': " +
-			"invalid type: *[]string"
+			"invalid type: *[]complex128"
 		if err.Error() != expected {
 			t.Errorf("Unexpected error coercing invalid value: %s", err.Error())
 		}
@@ -140,7 +226,95 @@ func TestCoerce(t *testing.T) {
 	}
 }
 
+func TestSupported(t *testing.T) {
+	for _, p := range []interface{}{
+		new(int), new(value.Secret), new([]time.Duration),
+		new(map[string]string),
+	} {
+		if !value.Supported(p) {
+			t.Errorf("%T should be supported", p)
+		}
+	}
+
+	for _, p := range []interface{}{
+		nil, 0, new(*int), new(time.Time), new([]value.Secret),
+		new(map[int]string),
+	} {
+		if value.Supported(p) {
+			t.Errorf("%T should not be supported", p)
+		}
+	}
+}
+
+func TestCoerceAll(t *testing.T) {
+	t.Run("Each element is coerced", func(t *testing.T) {
+		var p []time.Duration
+		v, err := value.CoerceAll([]string{"1s", "1m"}, &p)
+
+		if err != nil {
+			t.Errorf("Unexpected error coercing slice: %s", err.Error())
+		} else if s, ok := v.Pointer().([]time.Duration); !ok || len(s) != 2 ||
+			s[0] != time.Second || s[1] != time.Minute {
+			t.Errorf("Unexpected value coercing slice: %v", v.Pointer())
+		}
+	})
+
+	t.Run("Invalid elements will error", func(t *testing.T) {
+		var p []int
+		_, err := value.CoerceAll([]string{"1", "invalid"}, &p)
+
+		const prefix = "value.Data: cannot coerce 'invalid':"
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("Unexpected error coercing slice: %v", err)
+		}
+	})
+
+	t.Run("Each map entry is coerced", func(t *testing.T) {
+		var p map[string]string
+		v, err := value.CoerceAll([]string{"a=1", "b=x=y"}, &p)
+
+		if err != nil {
+			t.Errorf("Unexpected error coercing map: %s", err.Error())
+		} else if m, ok := v.Pointer().(map[string]string); !ok ||
+			len(m) != 2 || m["a"] != "1" || m["b"] != "x=y" {
+			t.Errorf("Unexpected value coercing map: %v", v.Pointer())
+		}
+	})
+
+	t.Run("Invalid map values will error", func(t *testing.T) {
+		var p map[string]bool
+		_, err := value.CoerceAll([]string{"a=invalid"}, &p)
+
+		const prefix = "value.Data: cannot coerce 'invalid':"
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("Unexpected error coercing map: %v", err)
+		}
+	})
+
+	t.Run("Non-slice types will error", func(t *testing.T) {
+		var p int
+		_, err := value.CoerceAll([]string{"1"}, &p)
+
+		expected := "value.Data: cannot coerce [\"1\"]: invalid type: *int"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error coercing slice: %v", err)
+		}
+	})
+}
+
 func TestData_AssignTo(t *testing.T) {
+	t.Run("Sensitive values are not included in errors", func(t *testing.T) {
+		var p int
+		d := value.New("hunter2")
+		d.Sensitive = true
+		err := d.AssignTo(&p)
+
+		expected := "value.Data ********, failed to assign to type int"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
 	t.Run("nil data will not error", func(t *testing.T) {
 		var p interface{}
 		d := value.New(nil)
@@ -150,6 +324,15 @@ func TestData_AssignTo(t *testing.T) {
 		}
 	})
 
+	t.Run("nil pointer data will not error", func(t *testing.T) {
+		var p *int
+		var i int
+
+		if err := value.New(p).AssignTo(&i); err != nil {
+			t.Errorf("Unexpected error assigning nil pointer: %s", err)
+		}
+	})
+
 	t.Run("nil assignable pointer will not error", func(t *testing.T) {
 		d := value.New("test")
 		if err := d.AssignTo(nil); err != nil {
@@ -279,13 +462,95 @@ func TestData_AssignTo(t *testing.T) {
 		}
 	})
 
-	t.Run("assignTo fails assign from an invalid type", func(t *testing.T) {
-		var p []string
+	t.Run("assignTo works with slices", func(t *testing.T) {
+		var p []int
+		v := []interface{}{float64(1), float64(2)}
+		d := value.New(v)
+		if err := d.AssignTo(&p); err != nil {
+			t.Errorf("Unexpected error assigning to value: %s", err.Error())
+		} else if len(p) != 2 || p[0] != 1 || p[1] != 2 {
+			t.Errorf("Expected '%v', got '%v' assigning from slice", v, p)
+		}
+	})
+
+	t.Run("assignTo works with nil slices", func(t *testing.T) {
+		p := []string{"test"}
 		var v []string
+		d := value.New(v)
+		if err := d.AssignTo(&p); err != nil {
+			t.Errorf("Unexpected error assigning to value: %s", err.Error())
+		} else if p != nil {
+			t.Errorf("Expected nil, got '%v' assigning from nil slice", p)
+		}
+	})
+
+	t.Run("assignTo works with maps", func(t *testing.T) {
+		var p map[string]int
+		v := map[string]interface{}{"a": float64(1)}
+		d := value.New(v)
+		if err := d.AssignTo(&p); err != nil {
+			t.Errorf("Unexpected error assigning to value: %s", err.Error())
+		} else if len(p) != 1 || p["a"] != 1 {
+			t.Errorf("Expected '%v', got '%v' assigning from map", v, p)
+		}
+	})
+
+	t.Run("assignTo works with nil maps", func(t *testing.T) {
+		p := map[string]string{"a": "b"}
+		var v map[string]string
+		d := value.New(v)
+		if err := d.AssignTo(&p); err != nil {
+			t.Errorf("Unexpected error assigning to value: %s", err.Error())
+		} else if p != nil {
+			t.Errorf("Expected nil, got '%v' assigning from nil map", p)
+		}
+	})
+
+	t.Run("assignTo fails assign to map values", func(t *testing.T) {
+		var p map[string]string
+		v := map[string]interface{}{"a": 1}
+		d := value.New(v)
+		err := d.AssignTo(&p)
+
+		expected := "value.Data: key 'a': value.Data: invalid cast of " +
+			"int to string"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error assigning to value: %v", err)
+		}
+	})
+
+	t.Run("assignTo fails assign from non-maps", func(t *testing.T) {
+		var p map[string]string
+		d := value.New([]string{"a"})
+		err := d.AssignTo(&p)
+
+		expected := "value.Data []string '[a]', failed to assign to type " +
+			"map[string]string"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error assigning to value: %v", err)
+		}
+	})
+
+	t.Run("assignTo fails assign to slice elements", func(t *testing.T) {
+		var p []string
+		v := []interface{}{"text", 1}
+		d := value.New(v)
+		err := d.AssignTo(&p)
+
+		expected := "value.Data: element 1: value.Data: invalid cast of " +
+			"int to string"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error assigning to value: %v", err)
+		}
+	})
+
+	t.Run("assignTo fails assign from an invalid type", func(t *testing.T) {
+		var p []complex128
+		var v []complex128
 		d := value.New(&v)
 		err := d.AssignTo(&p)
 
-		expected := "value.Data invalid pointer type: *[]string"
+		expected := "value.Data invalid pointer type: *[]complex128"
 		if err == nil || err.Error() != expected {
 			t.Errorf("Unexpected error assigning to value: %s", err.Error())
 		}
@@ -0,0 +1,37 @@
+package value
+
+// Redacted is shown in place of sensitive values.
+const Redacted = "********"
+
+// Secret is a string that never reveals its contents when formatted or
+// marshalled, making it suitable for passwords and tokens. The contents can be
+// obtained by converting the Secret to a string:
+//
+//     password := string(secret)
+type Secret string
+
+// String returns Redacted.
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted.
+func (s Secret) GoString() string {
+	return Redacted
+}
+
+// MarshalJSON returns Redacted as a JSON string.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+// MarshalText returns Redacted.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// Set the contents of the Secret, allowing it to be used as a flag.Value.
+func (s *Secret) Set(data string) error {
+	*s = Secret(data)
+	return nil
+}
@@ -0,0 +1,79 @@
+package value_test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+	"strings"
+	"testing"
+)
+
+func ExampleSecret() {
+	secret := value.Secret("hunter2")
+
+	fmt.Println(secret)
+	fmt.Println(string(secret))
+
+	// Output:
+	// ********
+	// hunter2
+}
+
+func TestSecret(t *testing.T) {
+	t.Run("Secrets are not revealed", func(t *testing.T) {
+		secret := value.Secret("hunter2")
+		b, err := json.Marshal(map[string]value.Secret{"password": secret})
+
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		s := fmt.Sprintf("%s %v %+v %#v %q %s", secret, secret, secret, secret,
+			secret, b)
+		if strings.Contains(s, "hunter2") {
+			t.Errorf("secret revealed: %s", s)
+		}
+	})
+
+	t.Run("Secrets can be used as flags", func(t *testing.T) {
+		var secret value.Secret
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Var(&secret, "secret", "a secret")
+
+		if err := flags.Parse([]string{"--secret", "hunter2"}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if string(secret) != "hunter2" {
+			t.Errorf("unexpected secret: %s", string(secret))
+		}
+	})
+
+	t.Run("Secrets can be coerced and assigned", func(t *testing.T) {
+		var secret value.Secret
+		var s string
+
+		d, err := value.Coerce("hunter2", &secret)
+
+		if err == nil {
+			err = d.AssignTo(&secret)
+		}
+
+		if err == nil {
+			err = value.New(secret).AssignTo(&s)
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if string(secret) != "hunter2" || s != "hunter2" {
+			t.Errorf("unexpected values: %s %s", string(secret), s)
+		}
+
+		if err = value.New(1).AssignTo(&secret); err == nil {
+			t.Error("expected error assigning int to Secret")
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package goconfigure
+
+import (
+	"fmt"
+	"github.com/domdavis/goconfigure/value"
+	"io"
+	"reflect"
+	"time"
+)
+
+// Redaction controls how the values of Sensitive options are written.
+type Redaction int
+
+// The ways in which the values of Sensitive options can be written.
+const (
+	// Mask replaces the value with a placeholder.
+	Mask Redaction = iota
+
+	// Omit leaves the option out entirely.
+	Omit
+
+	// Reveal writes the value as is.
+	Reveal
+)
+
+// spec holds the definition of an option, as used when describing the options
+// in config files or schemas.
+type spec struct {
+	key         string
+	description string
+	typeOf      reflect.Type
+	backstop    interface{}
+	required    bool
+	sensitive   bool
+	validators  []Validator
+}
+
+// specifier is implemented by options that can provide their definition.
+type specifier interface {
+	spec() spec
+}
+
+func (o *options) SensitiveOutput(r Redaction) {
+	o.redaction = r
+}
+
+func (o *options) WriteConfig(w io.Writer, format string) error {
+	if format == "" {
+		format = "json"
+	}
+
+	e, ok := encoder(format)
+
+	if !ok {
+		return fmt.Errorf("no encoder registered for format '%s'", format)
+	}
+
+	o.lock.Lock()
+	config, err := o.effective()
+	o.lock.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to write config: %s", err)
+	}
+
+	b, err := e.Encode(config)
+
+	if err != nil {
+		return fmt.Errorf("failed to write config: %s", err)
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// effective returns the current value of every option with a config key as a
+// nested config map.
+func (o *options) effective() (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+
+	for _, opt := range o.data {
+		s, ok := opt.(specifier)
+		p, isParser := opt.(parser)
+
+		if !ok || !isParser || s.spec().key == "" {
+			continue
+		}
+
+		var v interface{} = value.Redacted
+
+		switch {
+		case !s.spec().sensitive || o.redaction == Reveal:
+			v = plain(nonNil(p.current().value.Pointer(), s.spec().typeOf))
+		case o.redaction == Omit:
+			continue
+		}
+
+		if err := insert(config, s.spec().key, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// nonNil returns v, or the zero value of t if v is nil, so unset slices and
+// maps are written as empty ones rather than null.
+func nonNil(v interface{}, t reflect.Type) interface{} {
+	r := reflect.ValueOf(v)
+
+	switch {
+	case !r.IsValid() && t != nil:
+		return zero(t)
+	case (r.Kind() == reflect.Slice || r.Kind() == reflect.Map) && r.IsNil():
+		return zero(r.Type())
+	}
+
+	return v
+}
+
+// insert the value into the config map using the dotted key, creating any
+// nested objects required.
+func insert(config map[string]interface{}, key string, v interface{}) error {
+	path := splitKey(key)
+	node := config
+
+	for i, element := range path[:len(path)-1] {
+		child, ok := node[element]
+
+		if !ok {
+			child = map[string]interface{}{}
+			node[element] = child
+		}
+
+		if node, ok = child.(map[string]interface{}); !ok {
+			return fmt.Errorf("config key '%s' conflicts with '%s'", key,
+				joinKey(path[:i+1]))
+		}
+	}
+
+	last := path[len(path)-1]
+
+	if _, ok := node[last]; ok {
+		return fmt.Errorf("config key '%s' is used by more than one option",
+			key)
+	}
+
+	node[last] = v
+	return nil
+}
+
+// plain converts time.Duration values, including those in slices and maps, to
+// int64 nanoseconds so they can be read back from a config file. Secrets are
+// converted to strings so their contents are written.
+func plain(v interface{}) interface{} {
+	d := reflect.TypeOf(time.Duration(0))
+	i := reflect.TypeOf(int64(0))
+	r := reflect.ValueOf(v)
+
+	switch {
+	case !r.IsValid():
+		return v
+	case r.Type() == reflect.TypeOf(value.Secret("")):
+		return r.String()
+	case r.Type() == d:
+		return r.Int()
+	case r.Kind() == reflect.Slice && r.Type().Elem() == d:
+		s := reflect.MakeSlice(reflect.SliceOf(i), r.Len(), r.Len())
+
+		for n := 0; n < r.Len(); n++ {
+			s.Index(n).Set(r.Index(n).Convert(i))
+		}
+
+		return s.Interface()
+	case r.Kind() == reflect.Map && r.Type().Elem() == d:
+		m := reflect.MakeMapWithSize(reflect.MapOf(r.Type().Key(), i), r.Len())
+
+		for e := r.MapRange(); e.Next(); {
+			m.SetMapIndex(e.Key(), e.Value().Convert(i))
+		}
+
+		return m.Interface()
+	}
+
+	return v
+}
@@ -0,0 +1,254 @@
+package goconfigure_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/domdavis/goconfigure"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ExampleOptions_WriteConfig() {
+	var host, password string
+	var port int
+
+	opts := goconfigure.NewOptionsWithArgs([]string{"--port", "80"})
+	h := goconfigure.NewOption(&host, "host")
+	h.ConfigKey("server.host")
+	h.Default("localhost")
+	opts.Add(h)
+	p := goconfigure.NewOption(&port, "port")
+	p.LongFlag("port")
+	p.ConfigKey("server.port")
+	opts.Add(p)
+	pw := goconfigure.NewOption(&password, "password")
+	pw.ConfigKey("password")
+	pw.Default("hunter2")
+	pw.Sensitive()
+	opts.Add(pw)
+
+	if err := opts.Parse(nil); err != nil {
+		fmt.Println(err)
+	}
+
+	if err := opts.WriteConfig(os.Stdout, "json"); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "password": "********",
+	//   "server": {
+	//     "host": "localhost",
+	//     "port": 80
+	//   }
+	// }
+}
+
+func TestOptions_WriteConfig(t *testing.T) {
+	t.Run("Unknown formats will error", func(t *testing.T) {
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		err := opts.WriteConfig(&bytes.Buffer{}, "ini")
+
+		const expected = "no encoder registered for format 'ini'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Conflicting keys will error", func(t *testing.T) {
+		var a, b string
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		o := goconfigure.NewOption(&a, "a")
+		o.ConfigKey("a")
+		opts.Add(o)
+		o = goconfigure.NewOption(&b, "b")
+		o.ConfigKey("a.b")
+		opts.Add(o)
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		err := opts.WriteConfig(&bytes.Buffer{}, "")
+
+		const expected = "failed to write config: config key 'a.b' " +
+			"conflicts with 'a'"
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Sensitive values can be omitted or revealed", func(t *testing.T) {
+		var token string
+
+		for mode, expected := range map[goconfigure.Redaction]string{
+			goconfigure.Mask:   "{\n  \"token\": \"********\"\n}\n",
+			goconfigure.Omit:   "{}\n",
+			goconfigure.Reveal: "{\n  \"token\": \"secret\"\n}\n",
+		} {
+			opts := goconfigure.NewOptionsWithArgs(nil)
+			opt := goconfigure.NewOption(&token, "token")
+			opt.ConfigKey("token")
+			opt.Default("secret")
+			opt.Sensitive()
+			opts.Add(opt)
+			opts.SensitiveOutput(mode)
+			b := &bytes.Buffer{}
+
+			if err := opts.Parse(nil); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := opts.WriteConfig(b, "json"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if b.String() != expected {
+				t.Errorf("unexpected output for %d: %s", mode, b)
+			}
+		}
+	})
+
+	t.Run("Registered encoders will be used", func(t *testing.T) {
+		var message string
+
+		goconfigure.RegisterEncoder(".KV", goconfigure.EncoderFunc(
+			func(config map[string]interface{}) ([]byte, error) {
+				var lines []string
+
+				for k, v := range config {
+					lines = append(lines, fmt.Sprintf("%s=%v", k, v))
+				}
+
+				sort.Strings(lines)
+				return []byte(strings.Join(lines, "\n")), nil
+			}))
+
+		opts := goconfigure.NewOptionsWithArgs(nil)
+		opt := goconfigure.NewOption(&message, "message")
+		opt.ConfigKey("message")
+		opt.Default("hello")
+		opts.Add(opt)
+		b := &bytes.Buffer{}
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if err := opts.WriteConfig(b, "kv"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		} else if b.String() != "message=hello" {
+			t.Errorf("unexpected output: %s", b)
+		}
+	})
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run("Large values can be loaded as "+format, func(t *testing.T) {
+			out := &roundTrip{}
+			out.check(t, format, "-n", "18446744073709551615")
+		})
+	}
+
+	t.Run("Large values cannot be written as TOML", func(t *testing.T) {
+		out := &roundTrip{}
+		opts := out.options([]string{"-n", "18446744073709551615"})
+
+		if err := opts.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if err := opts.WriteConfig(io.Discard, "toml"); err == nil {
+			t.Error("expected error writing large value as TOML")
+		}
+	})
+
+	for _, format := range []string{"json", "toml", "yaml"} {
+		t.Run("Output can be loaded as "+format, func(t *testing.T) {
+			out := &roundTrip{}
+			out.check(t, format, "-l", "a", "-l", "b", "-m", "x=1s", "-t",
+				"1m", "-n", "42")
+		})
+
+		t.Run("Unset values can be loaded as "+format, func(t *testing.T) {
+			out := &roundTrip{}
+			out.check(t, format)
+		})
+	}
+}
+
+type roundTrip struct {
+	file    string
+	config  goconfigure.Option
+	list    []string
+	labels  map[string]time.Duration
+	timeout time.Duration
+	max     uint64
+}
+
+// check that the config written for the given arguments, in the given
+// format, can be loaded using ParseUsing.
+func (r *roundTrip) check(t *testing.T, format string, args ...string) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config."+format)
+	opts := r.options(args)
+
+	if err := opts.Parse(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f, err := os.Create(file)
+
+	if err == nil {
+		err = opts.WriteConfig(f, format)
+		_ = f.Close()
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	in := &roundTrip{}
+	opts = in.options([]string{"-c", file})
+
+	if err = opts.ParseUsing(in.config); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	got := fmt.Sprint(in.list, in.labels, in.timeout, in.max)
+	expected := fmt.Sprint(r.list, r.labels, r.timeout, r.max)
+
+	if got != expected {
+		t.Errorf("unexpected config: %s", got)
+	}
+}
+
+func (r *roundTrip) options(args []string) goconfigure.Options {
+	opts := goconfigure.NewOptionsWithArgs(args)
+	r.config = goconfigure.NewOption(&r.file, "config file")
+	r.config.ShortFlag('c')
+	opts.Add(r.config)
+
+	for _, o := range []struct {
+		pointer interface{}
+		flag    rune
+		key     string
+	}{
+		{&r.list, 'l', "nested.list"},
+		{&r.labels, 'm', "nested.labels"},
+		{&r.timeout, 't', "timeout"},
+		{&r.max, 'n', "max"},
+	} {
+		opt := goconfigure.NewOption(o.pointer, o.key)
+		opt.ShortFlag(o.flag)
+		opt.ConfigKey(o.key)
+		opts.Add(opt)
+	}
+
+	return opts
+}